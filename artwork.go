@@ -0,0 +1,438 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// ── Artwork pipeline ─────────────────────────────────────────
+//
+// generateArtworkBg builds bg.png by running the canvas through a list of ArtStage values.
+// AppSettings.ArtPipeline picks the stages and their order by name; when it is empty,
+// defaultArtPipeline reproduces the classic layout (black, wallpaper, thumbnail, caption).
+
+// ArtContext carries the inputs shared by all stages of one generateArtworkBg run.
+type ArtContext struct {
+	Art         image.Image // source artwork, already auto-cropped; nil when there is none
+	DisplayName string      // shortcut display name, for the caption
+	UseGlobalBg bool        // false in Art-on-Black mode: the wallpaper stage is skipped
+	Settings    AppSettings
+}
+
+// ArtStage is one step of the artwork pipeline. Apply may draw on canvas in place or
+// return a new image; the returned image is passed to the next stage.
+type ArtStage interface {
+	Name() string
+	Apply(canvas *image.NRGBA, ctx *ArtContext) *image.NRGBA
+}
+
+// Stage names accepted in AppSettings.ArtPipeline.
+const (
+	artStageBase      = "base"
+	artStageWallpaper = "wallpaper"
+	artStageTint      = "tint"
+	artStageBlur      = "blur"
+	artStageThumbnail = "thumbnail"
+	artStageRounded   = "rounded" // applies to the thumbnail, not the canvas
+	artStageGradient  = "gradient"
+	artStageText      = "text"
+	artStageVignette  = "vignette"
+)
+
+// defaultArtPipeline returns the stage names used when AppSettings.ArtPipeline is empty.
+func defaultArtPipeline(settings AppSettings) []string {
+	names := []string{artStageBase, artStageWallpaper}
+	if settings.ArtTintGlobalBg {
+		names = append(names, artStageTint)
+	}
+	names = append(names, artStageRounded, artStageThumbnail)
+	if settings.ArtTextOverlay {
+		names = append(names, artStageText)
+	}
+	return names
+}
+
+// buildArtPipeline turns the configured stage names into stages. "rounded" is attached to
+// the thumbnail stage wherever it appears; unknown names are logged and ignored. The
+// Show name on artwork setting (ArtTextOverlay) decides whether a caption is drawn: "text"
+// is dropped while it is off, and appended to a custom pipeline without it while it is on.
+func buildArtPipeline(settings AppSettings) []ArtStage {
+	names := settings.ArtPipeline
+	if len(names) == 0 {
+		names = defaultArtPipeline(settings)
+	}
+
+	thumb := &ThumbnailStage{}
+	var stages []ArtStage
+	hasText := false
+	for _, name := range names {
+		switch name {
+		case artStageBase:
+			stages = append(stages, BaseLayerStage{})
+		case artStageWallpaper:
+			stages = append(stages, GlobalBgStage{})
+		case artStageTint:
+			stages = append(stages, TintStage{Strength: settings.ArtTintStrength})
+		case artStageBlur:
+			stages = append(stages, BlurBgStage{Factor: 16})
+		case artStageThumbnail:
+			stages = append(stages, thumb)
+		case artStageRounded:
+			// Effective radius = FIXED_SCALE(2) * CFG_DEFAULT_THUMBRADIUS(20) = 40 px.
+			thumb.Effects = append(thumb.Effects, RoundedCornersStage{Radius: 40})
+		case artStageGradient:
+			stages = append(stages, GradientStage{Height: 0.35, MaxAlpha: 0.7})
+		case artStageText:
+			if settings.ArtTextOverlay {
+				stages = append(stages, TextOverlayStage{})
+				hasText = true
+			}
+		case artStageVignette:
+			stages = append(stages, VignetteStage{Strength: 0.5})
+		default:
+			log.Printf("buildArtPipeline: ignoring unknown stage %q", name)
+		}
+	}
+	if settings.ArtTextOverlay && !hasText {
+		stages = append(stages, TextOverlayStage{})
+	}
+	return stages
+}
+
+// maxCanvasMB caps the memory artwork generation may allocate for the canvas, the stages'
+// scratch images and the (already shrunk) source art. Devices have as little as 256 MB
+// RAM shared with the frontend.
+const maxCanvasMB = 20
+
+// scratchStage is implemented by stages that allocate images besides the canvas.
+type scratchStage interface {
+	// scratchBytes estimates what Apply allocates for a w×h canvas.
+	scratchBytes(w, h int) int
+}
+
+// optionalStage marks decorative stages runArtPipeline may drop to stay under maxCanvasMB.
+// The wallpaper and thumbnail are never dropped: they are the artwork itself.
+type optionalStage interface {
+	optional()
+}
+
+// runArtPipeline creates a screen-sized canvas and passes it through stages in order.
+// The source art is first shrunk to the thumbnail box (see shrinkArtToBox) so the full
+// decoded image needn't stay alive next to the canvas layers. If the estimate still
+// exceeds maxCanvasMB, optional stages are dropped and a warning is logged.
+func runArtPipeline(stages []ArtStage, ctx *ArtContext) *image.NRGBA {
+	screenW, screenH := screenDimensions()
+	ctx.Art = shrinkArtToBox(ctx.Art, screenW, screenH, ctx.Settings.ArtFit)
+
+	const limit = maxCanvasMB * 1024 * 1024
+	if need := pipelineBytes(stages, ctx.Art, screenW, screenH); need >= limit {
+		kept := stages[:0:0]
+		for _, st := range stages {
+			if _, ok := st.(optionalStage); !ok {
+				kept = append(kept, st)
+			}
+		}
+		log.Printf("runArtPipeline: warning: stages need %.1f MB (limit %d MB); dropping %d optional stage(s)",
+			float64(need)/(1024*1024), maxCanvasMB, len(stages)-len(kept))
+		stages = kept
+	}
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, screenW, screenH))
+	for _, st := range stages {
+		canvas = st.Apply(canvas, ctx)
+	}
+	return canvas
+}
+
+// pipelineBytes estimates the memory of running stages on a w×h canvas: the canvas, each
+// stage's scratch images and the source art.
+func pipelineBytes(stages []ArtStage, art image.Image, w, h int) int {
+	need := w * h * 4
+	for _, st := range stages {
+		if ss, ok := st.(scratchStage); ok {
+			need += ss.scratchBytes(w, h)
+		}
+	}
+	if art != nil {
+		need += art.Bounds().Dx() * art.Bounds().Dy() * 4
+	}
+	return need
+}
+
+// thumbnailBox returns the NextUI SCREEN_GAMELIST thumbnail box for a w×h screen.
+func thumbnailBox(w, h int) (maxW, maxH int) {
+	return int(float64(w) * 0.45), int(float64(h) * 0.60)
+}
+
+// shrinkArtToBox returns art scaled down to the size ThumbnailStage draws it at for a
+// screenW×screenH canvas (covering the box in Cover mode), so a large decoded source can
+// be freed before the canvas layers are allocated. Art no larger than that is returned
+// unchanged; it is never scaled up here.
+func shrinkArtToBox(art image.Image, screenW, screenH int, fit string) image.Image {
+	if art == nil {
+		return nil
+	}
+	maxW, maxH := thumbnailBox(screenW, screenH)
+	b := art.Bounds()
+	w, h := thumbnailFit(b.Dx(), b.Dy(), maxW, maxH)
+	if fit == ArtFitCover {
+		w, h, _ = thumbnailCover(b.Dx(), b.Dy(), maxW, maxH)
+	}
+	if w >= b.Dx() || h >= b.Dy() {
+		return art
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	xdraw.BiLinear.Scale(out, out.Bounds(), art, b, xdraw.Src, nil)
+	return out
+}
+
+// BaseLayerStage fills the canvas with opaque black, the fallback when the wallpaper is
+// absent or disabled.
+type BaseLayerStage struct{}
+
+func (BaseLayerStage) Name() string { return artStageBase }
+
+func (BaseLayerStage) Apply(canvas *image.NRGBA, _ *ArtContext) *image.NRGBA {
+	pix := canvas.Pix
+	for i := 0; i < len(pix); i += 4 {
+		pix[i], pix[i+1], pix[i+2], pix[i+3] = 0x00, 0x00, 0x00, 0xff
+	}
+	return canvas
+}
+
+// GlobalBgStage draws the device's global bg.png scaled to cover the canvas (centre-crop,
+// no letterbox). Skipped when ctx.UseGlobalBg is false or the wallpaper can't be loaded.
+type GlobalBgStage struct{}
+
+func (GlobalBgStage) Name() string { return artStageWallpaper }
+
+// scratchBytes counts the decoded wallpaper (assumed screen-sized) and its scaled copy.
+func (GlobalBgStage) scratchBytes(w, h int) int { return 2 * w * h * 4 }
+
+func (GlobalBgStage) Apply(canvas *image.NRGBA, ctx *ArtContext) *image.NRGBA {
+	if !ctx.UseGlobalBg {
+		return canvas
+	}
+	bgImg, err := loadImage(globalBgPath())
+	if err != nil {
+		return canvas
+	}
+	screenW, screenH := canvas.Bounds().Dx(), canvas.Bounds().Dy()
+	srcW, srcH := bgImg.Bounds().Dx(), bgImg.Bounds().Dy()
+	scale := max(float64(screenW)/float64(srcW), float64(screenH)/float64(srcH))
+	newW := int(float64(srcW) * scale)
+	newH := int(float64(srcH) * scale)
+	scaledBg := image.NewNRGBA(image.Rect(0, 0, newW, newH))
+	xdraw.BiLinear.Scale(scaledBg, scaledBg.Bounds(), bgImg, bgImg.Bounds(), xdraw.Src, nil)
+	// Centre-crop: offset into scaledBg so the canvas window is centred.
+	offX := (newW - screenW) / 2
+	offY := (newH - screenH) / 2
+	xdraw.Draw(canvas, canvas.Bounds(), scaledBg, image.Point{offX, offY}, xdraw.Src)
+	return canvas
+}
+
+// TintStage overlays the canvas with the artwork's dominant color at Strength opacity,
+// giving each shortcut a colour theme. Place it after the wallpaper; skipped in
+// Art-on-Black mode and when there is no art.
+type TintStage struct {
+	Strength float64
+}
+
+func (TintStage) Name() string { return artStageTint }
+
+func (TintStage) optional() {}
+
+func (s TintStage) Apply(canvas *image.NRGBA, ctx *ArtContext) *image.NRGBA {
+	if !ctx.UseGlobalBg || ctx.Art == nil || s.Strength <= 0 {
+		return canvas
+	}
+	tint := dominantColor(ctx.Art)
+	tint.A = uint8(255 * min(s.Strength, 1))
+	xdraw.Draw(canvas, canvas.Bounds(), image.NewUniform(tint), image.Point{}, xdraw.Over)
+	return canvas
+}
+
+// dominantColor averages img down to a single opaque pixel. BiLinear widens its kernel
+// when downscaling, so every source pixel contributes.
+func dominantColor(img image.Image) color.NRGBA {
+	px := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	xdraw.BiLinear.Scale(px, px.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+	c := px.NRGBAAt(0, 0)
+	c.A = 0xff
+	return c
+}
+
+// scaleToFit returns img scaled down to fit within maxW×maxH, preserving aspect ratio.
+func scaleToFit(img image.Image, maxW, maxH int) *image.NRGBA {
+	b := img.Bounds()
+	w, h := thumbnailFit(b.Dx(), b.Dy(), maxW, maxH)
+	out := image.NewNRGBA(image.Rect(0, 0, max(w, 1), max(h, 1)))
+	xdraw.BiLinear.Scale(out, out.Bounds(), img, b, xdraw.Src, nil)
+	return out
+}
+
+// BlurBgStage blurs everything drawn so far by shrinking the canvas by Factor and scaling
+// it back up. Place it after the wallpaper so the art stands out against a soft backdrop.
+type BlurBgStage struct {
+	Factor int
+}
+
+func (BlurBgStage) Name() string { return artStageBlur }
+
+func (BlurBgStage) optional() {}
+
+func (s BlurBgStage) scratchBytes(w, h int) int {
+	return max(1, w/s.Factor) * max(1, h/s.Factor) * 4
+}
+
+func (s BlurBgStage) Apply(canvas *image.NRGBA, _ *ArtContext) *image.NRGBA {
+	b := canvas.Bounds()
+	small := image.NewNRGBA(image.Rect(0, 0, max(1, b.Dx()/s.Factor), max(1, b.Dy()/s.Factor)))
+	xdraw.ApproxBiLinear.Scale(small, small.Bounds(), canvas, b, xdraw.Src, nil)
+	xdraw.BiLinear.Scale(canvas, b, small, small.Bounds(), xdraw.Src, nil)
+	return canvas
+}
+
+// ThumbnailStage scales the artwork into the NextUI SCREEN_GAMELIST thumbnail box, runs
+// Effects on the scaled thumbnail, and composites it — mirroring nextui.c:
+//
+//	max_w = screen_w * CFG_DEFAULT_GAMEARTWIDTH (0.45)
+//	max_h = screen_h * 0.60
+//	target_x = screen_w - new_w - SCALE1(BUTTON_MARGIN*3)  [30 px at FIXED_SCALE=2]
+//	center_y = screen_h*0.50 - new_h/2
+//
+// Skipped when ctx.Art is nil (forceBlack mode with no source art).
+type ThumbnailStage struct {
+	Effects []ArtStage // applied to the scaled thumbnail before compositing
+}
+
+func (*ThumbnailStage) Name() string { return artStageThumbnail }
+
+// scratchBytes counts the scaled thumbnail and, in Cover mode, the covering image it is
+// cropped from; together about twice the box.
+func (*ThumbnailStage) scratchBytes(w, h int) int {
+	maxW, maxH := thumbnailBox(w, h)
+	return 2 * maxW * maxH * 4
+}
+
+func (s *ThumbnailStage) Apply(canvas *image.NRGBA, ctx *ArtContext) *image.NRGBA {
+	artImg := ctx.Art
+	if artImg == nil {
+		return canvas
+	}
+	screenW, screenH := canvas.Bounds().Dx(), canvas.Bounds().Dy()
+	maxW, maxH := thumbnailBox(screenW, screenH)
+	var artW, artH int
+	var scaledArt *image.NRGBA
+	if ctx.Settings.ArtFit == ArtFitCover {
+		// Cover: fill the whole box, centre-cropping the overflow.
+		coverW, coverH, crop := thumbnailCover(artImg.Bounds().Dx(), artImg.Bounds().Dy(), maxW, maxH)
+		covered := image.NewNRGBA(image.Rect(0, 0, coverW, coverH))
+		xdraw.BiLinear.Scale(covered, covered.Bounds(), artImg, artImg.Bounds(), xdraw.Over, nil)
+		artW, artH = min(maxW, coverW), min(maxH, coverH)
+		scaledArt = image.NewNRGBA(image.Rect(0, 0, artW, artH))
+		xdraw.Draw(scaledArt, scaledArt.Bounds(), covered, crop, xdraw.Src)
+	} else {
+		artW, artH = thumbnailFit(artImg.Bounds().Dx(), artImg.Bounds().Dy(), maxW, maxH)
+		scaledArt = image.NewNRGBA(image.Rect(0, 0, artW, artH))
+		xdraw.BiLinear.Scale(scaledArt, scaledArt.Bounds(), artImg, artImg.Bounds(), xdraw.Over, nil)
+	}
+	if ctx.Settings.ArtSharpen > 0 {
+		sharpenImage(scaledArt, ctx.Settings.ArtSharpen)
+	}
+	for _, fx := range s.Effects {
+		scaledArt = fx.Apply(scaledArt, ctx)
+	}
+
+	const rightMargin = 30 // SCALE1(BUTTON_MARGIN * 3) at FIXED_SCALE=2
+	targetX := max(0, screenW-artW-rightMargin)
+	centerY := screenH/2 - artH/2
+	artDst := image.Rect(targetX, centerY, targetX+artW, centerY+artH)
+	xdraw.Draw(canvas, artDst, scaledArt, image.Point{}, xdraw.Over)
+	return canvas
+}
+
+// RoundedCornersStage makes pixels outside quarter circles of Radius at each corner
+// transparent. Mirrors GFX_ApplyRoundedCorners_8888 in nextui.
+type RoundedCornersStage struct {
+	Radius int
+}
+
+func (RoundedCornersStage) Name() string { return artStageRounded }
+
+func (s RoundedCornersStage) Apply(img *image.NRGBA, _ *ArtContext) *image.NRGBA {
+	applyRoundedCorners(img, s.Radius)
+	return img
+}
+
+// GradientStage darkens the bottom Height fraction of the canvas with a black gradient
+// reaching MaxAlpha at the bottom edge. Useful under a caption.
+type GradientStage struct {
+	Height   float64
+	MaxAlpha float64
+}
+
+func (GradientStage) Name() string { return artStageGradient }
+
+func (GradientStage) optional() {}
+
+func (s GradientStage) Apply(canvas *image.NRGBA, _ *ArtContext) *image.NRGBA {
+	b := canvas.Bounds()
+	h := int(float64(b.Dy()) * s.Height)
+	if h <= 0 {
+		return canvas
+	}
+	top := b.Max.Y - h
+	for y := top; y < b.Max.Y; y++ {
+		alpha := uint8(255 * s.MaxAlpha * float64(y-top+1) / float64(h))
+		row := image.Rect(b.Min.X, y, b.Max.X, y+1)
+		xdraw.Draw(canvas, row, image.NewUniform(color.NRGBA{0, 0, 0, alpha}), image.Point{}, xdraw.Over)
+	}
+	return canvas
+}
+
+// TextOverlayStage captions the canvas with ctx.DisplayName (see drawTextOverlay).
+type TextOverlayStage struct{}
+
+func (TextOverlayStage) Name() string { return artStageText }
+
+func (TextOverlayStage) Apply(canvas *image.NRGBA, ctx *ArtContext) *image.NRGBA {
+	if ctx.DisplayName != "" {
+		drawTextOverlay(canvas, ctx.DisplayName, ctx.Settings.ArtTextPosition, ctx.Settings.ArtTextFontPath)
+	}
+	return canvas
+}
+
+// VignetteStage darkens the canvas towards its corners; Strength is the darkening at the
+// very corner (0–1).
+type VignetteStage struct {
+	Strength float64
+}
+
+func (VignetteStage) Name() string { return artStageVignette }
+
+func (VignetteStage) optional() {}
+
+func (s VignetteStage) Apply(canvas *image.NRGBA, _ *ArtContext) *image.NRGBA {
+	b := canvas.Bounds()
+	cx, cy := float64(b.Dx())/2, float64(b.Dy())/2
+	maxD2 := cx*cx + cy*cy
+	for y := 0; y < b.Dy(); y++ {
+		dy := float64(y) + 0.5 - cy
+		row := canvas.Pix[y*canvas.Stride:]
+		for x := 0; x < b.Dx(); x++ {
+			dx := float64(x) + 0.5 - cx
+			f := 1 - s.Strength*(dx*dx+dy*dy)/maxD2
+			f = math.Max(0, f)
+			i := x * 4
+			row[i] = uint8(float64(row[i]) * f)
+			row[i+1] = uint8(float64(row[i+1]) * f)
+			row[i+2] = uint8(float64(row[i+2]) * f)
+		}
+	}
+	return canvas
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+func TestLargeArtKeepsWallpaperStage(t *testing.T) {
+	savedPlatform, savedBrick := platform, isBrick
+	t.Cleanup(func() { platform, isBrick = savedPlatform, savedBrick })
+	platform, isBrick = PlatformTG5040, false
+
+	w, h := screenDimensions()
+	stages := buildArtPipeline(AppSettings{ArtTintGlobalBg: true, ArtTintStrength: 0.3})
+	art := image.NewNRGBA(image.Rect(0, 0, 2400, 2400)) // ~23 MB decoded
+
+	shrunk := shrinkArtToBox(art, w, h, "")
+	maxW, maxH := thumbnailBox(w, h)
+	if b := shrunk.Bounds(); b.Dx() > maxW || b.Dy() > maxH {
+		t.Fatalf("shrunk art is %v, want within %d×%d", b.Size(), maxW, maxH)
+	}
+	if need := pipelineBytes(stages, shrunk, w, h); need >= maxCanvasMB*1024*1024 {
+		t.Errorf("default pipeline with shrunk art needs %d bytes, over the %d MB limit", need, maxCanvasMB)
+	}
+
+	small := image.NewNRGBA(image.Rect(0, 0, 96, 144))
+	if got := shrinkArtToBox(small, w, h, ""); got != image.Image(small) {
+		t.Error("art smaller than the box was rescaled")
+	}
+}
+
+func TestArtPipelineFollowsTextOverlay(t *testing.T) {
+	hasText := func(stages []ArtStage) bool {
+		for _, st := range stages {
+			if _, ok := st.(TextOverlayStage); ok {
+				return true
+			}
+		}
+		return false
+	}
+	custom := []string{artStageBase, artStageWallpaper, artStageThumbnail}
+	if !hasText(buildArtPipeline(AppSettings{ArtPipeline: custom, ArtTextOverlay: true})) {
+		t.Error("custom pipeline without \"text\" has no caption with Show name on artwork on")
+	}
+	withText := append(slices.Clone(custom), artStageText)
+	if hasText(buildArtPipeline(AppSettings{ArtPipeline: withText})) {
+		t.Error("custom pipeline with \"text\" draws a caption with Show name on artwork off")
+	}
+}
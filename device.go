@@ -1,29 +1,208 @@
 package main
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
+	"image/jpeg"
 	"image/png"
+	"io"
 	"log"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/webp"
 )
 
+// ── Device detection ─────────────────────────────────────────
+
+// deviceTreeModelPath is the standard Linux file holding the board's model string.
+const deviceTreeModelPath = "/proc/device-tree/model"
+
+// dtsModels maps substrings of the device-tree model (matched case-insensitively, first
+// match wins) to platforms, for starting the app outside NextUI's launch.sh. The SoC names
+// catch boards whose model string doesn't name the device.
+var dtsModels = []struct {
+	match    string
+	platform Platform
+}{
+	{"smart pro s", PlatformTG5050},
+	{"sun55iw3", PlatformTG5050}, // Allwinner A523
+	{"brick", PlatformTG5040},
+	{"smart pro", PlatformTG5040},
+	{"sun50iw10", PlatformTG5040}, // Allwinner A133
+}
+
+// readDeviceTreeModel returns the device-tree model string, or "" when it can't be read
+// (e.g. on macOS). The kernel terminates it with a NUL byte.
+func readDeviceTreeModel() string {
+	data, err := os.ReadFile(deviceTreeModelPath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(data), "\x00"))
+}
+
+// detectPlatformFromDTS matches the device-tree model against dtsModels. It is the
+// fallback in main when PLATFORM is not set.
+func detectPlatformFromDTS() (Platform, bool) {
+	model := strings.ToLower(readDeviceTreeModel())
+	if model == "" {
+		return "", false
+	}
+	for _, m := range dtsModels {
+		if strings.Contains(model, m.match) {
+			return m.platform, true
+		}
+	}
+	log.Printf("detectPlatformFromDTS: unknown model %q", model)
+	return "", false
+}
+
 // ── Device paths ─────────────────────────────────────────────
 
 const (
 	sdcardPath     = "/mnt/SDCARD"
-	romsPath       = sdcardPath + "/Roms"
-	toolsPath      = sdcardPath + "/Tools"
-	emusUserPath   = sdcardPath + "/Emus"
 	systemPaksPath = sdcardPath + "/.system"
 )
 
+// sdcardMountPaths lists the mount points checked by discoverSDCards, primary card first.
+var sdcardMountPaths = []string{
+	sdcardPath,
+	"/mnt/EXSD",
+	"/mnt/SDCARD2",
+	"/mnt/sdcard1",
+	"/mnt/usb",
+}
+
+// getSDCardRoot returns the card the app operates on: SDCARD_PATH when set (NextUI exports
+// it, main sets it to the resolveSDCardRoot result, and the startup card picker overrides
+// it), otherwise the default mount point, or the mock card on macOS.
+func getSDCardRoot() string {
+	if sdcard := os.Getenv("SDCARD_PATH"); sdcard != "" {
+		return sdcard
+	}
+	if platform == PlatformMac {
+		return macSDCardRoot()
+	}
+	return sdcardPath
+}
+
+// resolveSDCardRoot picks the card to use when the app starts, and where the choice came
+// from (for the startup log):
+//
+//  1. "env": the SDCARD_PATH environment variable
+//  2. "settings": sdcard_path in the default card's settings.json (macOS: the mock card's)
+//  3. "discovered": the first card found by discoverSDCards
+//
+// On macOS all steps are handled by macSDCardRoot ("mock"). "default" means nothing
+// matched and the default mount point is used.
+func resolveSDCardRoot() (path, source string) {
+	if platform == PlatformMac {
+		return macSDCardRoot(), "mock"
+	}
+	if env := os.Getenv("SDCARD_PATH"); env != "" {
+		return env, "env"
+	}
+	if p := readSDCardPathSetting(sdcardPath); p != "" {
+		return p, "settings"
+	}
+	if cards := discoverSDCards(); len(cards) > 0 {
+		return cards[0], "discovered"
+	}
+	return sdcardPath, "default"
+}
+
+// readSDCardPathSetting returns the sdcard_path value from the settings.json on the card at
+// root, or "" if the file is missing, unreadable or doesn't set it. It is read directly
+// rather than through loadSettings, which depends on the card being resolved already.
+func readSDCardPathSetting(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, ".userdata", "shared", "Shortcuts", "settings.json"))
+	if err != nil {
+		return ""
+	}
+	var boot struct {
+		SDCARDPath string `json:"sdcard_path"`
+	}
+	if err := json.Unmarshal(data, &boot); err != nil {
+		return ""
+	}
+	return boot.SDCARDPath
+}
+
+// discoverSDCards returns the mounted cards from sdcardMountPaths that contain a Roms/
+// directory. Mount points that resolve to the same directory are reported once.
+func discoverSDCards() []string {
+	var cards []string
+	seen := make(map[string]bool)
+	for _, p := range sdcardMountPaths {
+		info, err := os.Stat(filepath.Join(p, "Roms"))
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		resolved, err := filepath.EvalSymlinks(p)
+		if err != nil {
+			resolved = p
+		}
+		if seen[resolved] {
+			continue
+		}
+		seen[resolved] = true
+		cards = append(cards, p)
+	}
+	return cards
+}
+
+// saveExtensions lists the save file formats recognised by scanSaves.
+var saveExtensions = map[string]bool{
+	".srm": true, // RetroArch SRAM
+	".sav": true, // battery save
+	".fs":  true, // FreeIntv / flash save
+	".mcr": true, // PlayStation memory card
+}
+
+// archiveExtensions lists compressed ROM formats. The shortcut's m3u points at the archive
+// itself; extracting it is left to the emulator.
+var archiveExtensions = map[string]bool{
+	".7z":  true,
+	".zip": true,
+	".rar": true,
+	".gz":  true,
+	".bz2": true,
+}
+
+// coreSidecarExt is the extension of a per-ROM core override file next to the ROM, e.g.
+// "Fire Red.core" containing "mgba". It is not listed as a ROM.
+const coreSidecarExt = ".core"
+
+// Files in a save state shortcut folder (see createSaveStateShortcut): shortcutROMFile
+// holds the ROM as an .m3u entry ("../<console dir>/<path>"; older shortcuts hold an
+// absolute path) and shortcutSaveStateFile is a copy of the state to load. The bridge
+// passes both to the emulator pak's launch.sh.
+const (
+	shortcutROMFile       = "rom"
+	shortcutSaveStateFile = "savestate"
+)
+
 // shortcutPrefix is the Zero Width No-Break Space (U+FEFF) prepended to Bottom-position
 // shortcut folder names so they sort after Z in NextUI without showing any visible prefix
 // in the menu. NextUI sorts via strcasecmp; U+FEFF's first UTF-8 byte (0xEF = 239) > 'z'
@@ -38,6 +217,36 @@ const legacyShortcutPrefix = "\u2605 "
 // bridgeEmuTag is the tag used for tool shortcuts.
 const bridgeEmuTag = "SHORTCUT"
 
+// directBridgeEmuTag is the tag of tool shortcuts to paks with their own shortcut launcher
+// (directLaunchScript), run by SHORTCUT_DIRECT.pak instead of SHORTCUT.pak.
+const directBridgeEmuTag = "SHORTCUT_DIRECT"
+
+// directLaunchScript is the file a tool pak provides to handle shortcut launches itself.
+const directLaunchScript = "shortcut_launch.sh"
+
+// isToolTag reports whether tag belongs to a tool shortcut (either bridge).
+func isToolTag(tag string) bool {
+	return tag == bridgeEmuTag || tag == directBridgeEmuTag
+}
+
+// isBridgePak reports whether the pak folder name (e.g. "SHORTCUT.pak", possibly with
+// .disabled) is one of this app's bridge emulators, which are never tools to shortcut.
+// New bridges are covered by adding their tag to isToolTag.
+func isBridgePak(name string) bool {
+	base, ok := strings.CutSuffix(strings.TrimSuffix(name, ".disabled"), ".pak")
+	return ok && isToolTag(base)
+}
+
+// toolShortcutTag returns the bridge tag for a tool shortcut to pakPath: directBridgeEmuTag
+// when the pak contains an executable directLaunchScript, bridgeEmuTag otherwise.
+func toolShortcutTag(pakPath string) string {
+	info, err := os.Stat(filepath.Join(pakPath, directLaunchScript))
+	if err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0 {
+		return directBridgeEmuTag
+	}
+	return bridgeEmuTag
+}
+
 // ShortcutPosition controls where a shortcut appears in the file listing.
 type ShortcutPosition int
 
@@ -52,15 +261,88 @@ const (
 	ShortcutPositionAlpha
 )
 
+// String returns the lower-case tier name used in logs and the shortcut registry.
+func (p ShortcutPosition) String() string {
+	switch p {
+	case ShortcutPositionTop:
+		return "top"
+	case ShortcutPositionAlpha:
+		return "alpha"
+	default:
+		return "bottom"
+	}
+}
+
 // topPrefix is the sort prefix for top-of-list shortcuts.
 // NextUI's trimSortingMeta strips "{digits}) " from display names, so "0) Foo" shows as "Foo".
 const topPrefix = "0) "
 
+// sortPrefixes are the folder-name prefixes that place shortcuts at the Top or Bottom of
+// the menu. Current prefixes are used for new folders; old ones (previous settings, the
+// built-in defaults and the legacy ★) are still recognised when scanning.
+type sortPrefixes struct {
+	Top, Bottom       string
+	oldTop, oldBottom []string
+}
+
+// sortPrefixes returns the prefixes configured in s, falling back to topPrefix and
+// shortcutPrefix for unset or whitespace-only ones (see checkSortPrefixes).
+func (s AppSettings) sortPrefixes() sortPrefixes {
+	p := sortPrefixes{Top: s.TopPrefix, Bottom: s.BottomPrefix}
+	if strings.TrimSpace(p.Top) == "" {
+		p.Top = topPrefix
+	}
+	if strings.TrimSpace(p.Bottom) == "" {
+		p.Bottom = shortcutPrefix
+	}
+	p.oldTop = []string{s.AppliedTopPrefix, topPrefix}
+	p.oldBottom = []string{s.AppliedBottomPrefix, shortcutPrefix, legacyShortcutPrefix}
+	return p
+}
+
+// defaultMediaSubdir is the artwork folder NextUI reads, both in console folders (source
+// art) and in shortcut folders (bg.png).
+const defaultMediaSubdir = ".media"
+
+// mediaSubdir returns the configured artwork folder name, or defaultMediaSubdir when it is
+// unset or not a plain folder name.
+func (s AppSettings) mediaSubdir() string {
+	name := strings.TrimSpace(s.MediaSubdir)
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return defaultMediaSubdir
+	}
+	return name
+}
+
+// match returns the position tier a folder name sorts into and the prefix that put it
+// there ("" for Alpha). Current prefixes win over old ones.
+func (p sortPrefixes) match(name string) (ShortcutPosition, string) {
+	for _, pre := range append([]string{p.Bottom}, p.oldBottom...) {
+		if pre != "" && strings.HasPrefix(name, pre) {
+			return ShortcutPositionBottom, pre
+		}
+	}
+	for _, pre := range append([]string{p.Top}, p.oldTop...) {
+		if pre != "" && strings.HasPrefix(name, pre) {
+			return ShortcutPositionTop, pre
+		}
+	}
+	return ShortcutPositionAlpha, ""
+}
+
 // shortcutMarkerFile is the hidden file written inside every new shortcut folder.
 // Its presence identifies shortcuts that have no ZWS/★ prefix (Top/Alpha positions),
 // and its content is the clean display name (e.g. "Battletoads (World)").
 const shortcutMarkerFile = ".shortcut"
 
+// toolEnvFile stores a tool shortcut's environment variables as a JSON object. It is the
+// source of truth read back by the app; toolEnvScript is the shell export snippet generated
+// from it, which the bridge emu sources before exec'ing the tool's launch.sh.
+const (
+	toolEnvFile   = "env.json"
+	toolEnvScript = "env.sh"
+)
+
 // ── Data types ───────────────────────────────────────────────
 
 // ConsoleDir represents a ROM console directory.
@@ -70,6 +352,9 @@ type ConsoleDir struct {
 	Path       string // full path to the directory
 	Display    string // display name without tag
 	IsDisabled bool   // true if the folder name ends with .disabled
+	IsEmpty    bool   // true if the folder has no entries at all (listed only when ShowHidden is on)
+	HiddenOnly bool   // true if the folder contains only hidden entries, e.g. .DS_Store (ditto)
+	CorePath   string // RetroArch core for Tag from the core map (see coreForTag); "" if unmapped
 }
 
 // ROMFile represents a ROM file or game folder within a console directory.
@@ -77,16 +362,60 @@ type ROMFile struct {
 	Name        string // filename (e.g. "Battletoads (World).md") or dir name for folder-based games
 	Path        string // full path
 	Display     string // display name without extension (no [disabled] suffix — used for artwork lookup)
+	Subdir      string // folder relative to the console dir (e.g. "RPG"); "" at the top level
 	IsMultiDisc bool   // true if this is a multi-disc folder (subdir containing {name}.m3u)
 	IsCueFolder bool   // true if this is a single-disc folder (subdir containing {name}.cue)
+	IsArchive   bool   // true if the file is a compressed archive (see archiveExtensions)
 	IsDisabled  bool   // true if the entry ends with .disabled (visible only when ShowHidden is on)
+	HasSave     bool   // true if a matching save file exists (set by crossReferenceSaves)
+
+	SizeBytes int64     // file size; 0 for multi-disc and CUE folders until fillFolderSizes
+	ModTime   time.Time // modification time of the file or folder
+
+	CoreOverride string // emulator core from a <Display>.core sidecar; "" uses the console default
+}
+
+// SaveState is a save state NextUI wrote for a ROM, e.g.
+// .userdata/tg5040/GBA-gpsp/Pokemon Emerald.gba.st1 for slot 1.
+type SaveState struct {
+	Path    string
+	Slot    int
+	ModTime time.Time
+}
+
+// SaveFile represents a save file belonging to a console.
+type SaveFile struct {
+	Name       string // filename, e.g. "Pokemon Emerald.gba.sav"
+	Path       string // full path
+	ROMDisplay string // name without the save extension, e.g. "Pokemon Emerald.gba"
+	Format     string // save extension without the dot, e.g. "sav"
 }
 
 // ToolPak represents a tool .pak directory.
 type ToolPak struct {
-	Name    string // e.g. "SDLReader"
-	Path    string // full path, e.g. "/mnt/SDCARD/Tools/tg5040/SDLReader.pak"
-	Display string // display name
+	Name     string // e.g. "SDLReader"
+	Path     string // full path, e.g. "/mnt/SDCARD/Tools/tg5040/SDLReader.pak"
+	Display  string // display name
+	IsSystem bool   // true for built-in paks from .system/<platform>/ (see scanSystemTools)
+	Subdir   string // folder relative to the tools dir (e.g. "Emulation"); "" at the top level
+
+	Description string // from pak.json, e.g. "Classic adventure game engine"; "" if none
+	Version     string // from pak.json, e.g. "2.7.1"; "" if none
+
+	// IsShortcutTarget is false for paks whose pak.json sets "no_shortcut", e.g. shared
+	// library paks that are only meant to be launched by other tools.
+	IsShortcutTarget bool
+}
+
+// PakMeta is the subset of a tool pak's pak.json that this app understands, e.g.
+//
+//	{"name": "ScummVM", "description": "Classic adventure game engine", "version": "2.7.1"}
+type PakMeta struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Version     string   `json:"version"`
+	Requires    []string `json:"requires"`    // other paks that must be installed, e.g. ["ScummVM.pak"]
+	NoShortcut  bool     `json:"no_shortcut"` // true for paks that shouldn't get a shortcut
 }
 
 // Shortcut represents an existing shortcut on the device.
@@ -97,82 +426,57 @@ type Shortcut struct {
 	Path       string // full path to shortcut folder
 	IsTool     bool   // true if this is a tool shortcut
 	TargetPath string // resolved target (ROM file path or tool .pak path)
+	MediaDir   string // the shortcut's artwork folder, e.g. <Path>/.media (see mediaSubdir)
+
+	// IsNameMismatch is true when the marker's display name no longer matches the folder
+	// name, e.g. after the folder was renamed on a computer. See folderDisplayName.
+	IsNameMismatch bool
+
+	Env map[string]string // extra environment variables exported by the bridge emu (tool shortcuts only)
+
+	AccessCount  int       // approximate launch count from the marker (see refreshAccessStats)
+	LastAccessed time.Time // last launch seen by refreshAccessStats; zero if never refreshed
+
+	ConsoleDirName string // ROM shortcuts: console folder from the marker; "" for older markers
+
+	// IsSaveState is true for shortcuts made by createSaveStateShortcut. They launch through
+	// the tool bridge but are ROM shortcuts otherwise: IsTool is false and TargetPath is
+	// the ROM.
+	IsSaveState bool
+
+	Position ShortcutPosition // tier from the folder name prefix (see sortPrefixes.match)
+
+	TargetMissingSince time.Time // when the target was first seen missing; zero if present
+
+	CreatedAt time.Time // from the marker; zero for shortcuts made before it was recorded
+
+	Description string // tool shortcuts: pak.json description recorded in the marker; "" if none
+	Version     string // tool shortcuts: pak.json version recorded in the marker; "" if none
 }
 
 // ── Scanning functions ───────────────────────────────────────
 
-// getBasePaths returns the device paths, adjusted for macOS development.
+// getBasePaths returns the Roms, Tools and Emus paths on the card from getSDCardRoot.
 func getBasePaths() (roms, tools, emus string) {
-	if platform == PlatformMac {
-		// Use a local mock directory structure for development
-		cwd, _ := os.Getwd()
-		mock := filepath.Join(cwd, "mock_sdcard")
-		return filepath.Join(mock, "Roms"),
-			filepath.Join(mock, "Tools", "tg5040"),
-			filepath.Join(mock, "Emus", "tg5040")
-	}
-	return romsPath,
-		filepath.Join(toolsPath, string(platform)),
-		filepath.Join(emusUserPath, string(platform))
+	sdcard := getSDCardRoot()
+	return filepath.Join(sdcard, "Roms"),
+		filepath.Join(sdcard, "Tools", platform.dirName()),
+		filepath.Join(sdcard, "Emus", platform.dirName())
 }
 
-// scanConsoleDirs returns all ROM console directories (non-shortcut).
+// scanConsoleDirs returns all ROM console directories (non-shortcut), sorted by display name.
 // When showHidden is false (default): dot-prefixed and .disabled folders are skipped,
 // and console dirs with no visible ROM content are also skipped.
 // When showHidden is true: .disabled folders and dot-dirs that have a (TAG) suffix are
 // included; empty dirs are shown; Mac dotfiles (dot-dirs without a tag) are still excluded.
-func scanConsoleDirs(showHidden bool) ([]ConsoleDir, error) {
-	romsDir, _, _ := getBasePaths()
-	entries, err := os.ReadDir(romsDir)
-	if err != nil {
-		return nil, fmt.Errorf("reading roms dir: %w", err)
-	}
-
+func scanConsoleDirs(showHidden bool, prefixes sortPrefixes) ([]ConsoleDir, error) {
+	consoleCh, errCh := scanConsoleDirsChan(showHidden, prefixes)
 	var consoles []ConsoleDir
-	for _, e := range entries {
-		if !e.IsDir() {
-			continue
-		}
-		name := e.Name()
-		fullPath := filepath.Join(romsDir, name)
-
-		if isShortcutFolder(fullPath) {
-			continue
-		}
-
-		if !showHidden {
-			if isHidden(name) {
-				continue
-			}
-			// Skip console dirs with no visible ROM content (empty or dotfiles only).
-			if !dirHasVisibleContent(fullPath) {
-				continue
-			}
-		} else {
-			// With showHidden: still exclude Mac dotfiles (dot-dirs without a (TAG)).
-			if isMacDotfile(name) || name == "map.txt" {
-				continue
-			}
-		}
-
-		// For .disabled folders strip the suffix before extracting tag/display.
-		isDisabled := strings.HasSuffix(name, ".disabled")
-		baseName := name
-		if isDisabled {
-			baseName = strings.TrimSuffix(name, ".disabled")
-		}
-
-		tag := extractTag(baseName)
-		if tag == "" {
-			continue // no emu tag — skip
-		}
-		consoles = append(consoles, ConsoleDir{
-			Name:       name,
-			Tag:        tag,
-			Path:       fullPath,
-			Display:    extractDisplayName(baseName),
-			IsDisabled: isDisabled,
-		})
+	for c := range consoleCh {
+		consoles = append(consoles, c)
+	}
+	if err := <-errCh; err != nil {
+		return nil, err
 	}
 
 	sort.Slice(consoles, func(i, j int) bool {
@@ -182,739 +486,4233 @@ func scanConsoleDirs(showHidden bool) ([]ConsoleDir, error) {
 	return consoles, nil
 }
 
-// scanROMs returns all ROM files in a console directory.
-// When showHidden is false (default): hidden and .disabled entries are skipped.
-// When showHidden is true: .disabled entries are included with IsDisabled set; known
-// Mac artifacts (.DS_Store, map.txt, etc.) are always excluded.
-func scanROMs(consoleDir string, showHidden bool) ([]ROMFile, error) {
-	entries, err := os.ReadDir(consoleDir)
-	if err != nil {
-		return nil, fmt.Errorf("reading rom dir: %w", err)
+// consolePrescan holds the result of the startup console scan (startConsolePrescan) until
+// the first console picker takes it, so the first Add ROM Shortcut opens without waiting
+// for a full Roms/ scan. Later pickers scan afresh.
+var consolePrescan struct {
+	sync.RWMutex
+	done       chan struct{} // closed when the scan finishes; nil if no scan was started
+	consoles   []ConsoleDir
+	err        error
+	showHidden bool
+	taken      bool
+}
+
+// startConsolePrescan runs scanConsoleDirs in the background for takePrescannedConsoles.
+// ROMs are not counted here; the picker starts that when it is first opened.
+func startConsolePrescan(settings AppSettings) {
+	done := make(chan struct{})
+	consolePrescan.Lock()
+	consolePrescan.done = done
+	consolePrescan.showHidden = settings.ShowHidden
+	consolePrescan.Unlock()
+
+	go func() {
+		consoles, err := scanConsoleDirs(settings.ShowHidden, settings.sortPrefixes())
+		consolePrescan.Lock()
+		consolePrescan.consoles, consolePrescan.err = consoles, err
+		consolePrescan.Unlock()
+		close(done)
+	}()
+}
+
+// consolePrescanPending returns a channel that is closed when the startup console scan
+// finishes, or nil when there is none to wait for (never started, or already taken).
+func consolePrescanPending() <-chan struct{} {
+	consolePrescan.RLock()
+	defer consolePrescan.RUnlock()
+	if consolePrescan.done == nil || consolePrescan.taken {
+		return nil
 	}
+	select {
+	case <-consolePrescan.done:
+		return nil
+	default:
+		return consolePrescan.done
+	}
+}
 
-	var roms []ROMFile
-	for _, e := range entries {
-		name := e.Name()
+// takePrescannedConsoles returns the startup console scan if it has finished without error
+// for the same showHidden value and hasn't been taken yet. It can be taken only once.
+func takePrescannedConsoles(showHidden bool) ([]ConsoleDir, bool) {
+	consolePrescan.Lock()
+	defer consolePrescan.Unlock()
+	if consolePrescan.done == nil || consolePrescan.taken {
+		return nil, false
+	}
+	select {
+	case <-consolePrescan.done:
+	default:
+		return nil, false // still scanning
+	}
+	consolePrescan.taken = true
+	if consolePrescan.err != nil || consolePrescan.showHidden != showHidden {
+		return nil, false
+	}
+	log.Printf("takePrescannedConsoles: using %d consoles from the startup scan", len(consolePrescan.consoles))
+	// The picker sorts its consoles in place.
+	return slices.Clone(consolePrescan.consoles), true
+}
 
-		if !showHidden {
-			if isHidden(name) {
+// scanConsoleDirsChan is the streaming variant of scanConsoleDirs for large Roms directories.
+// Console dirs are sent unsorted as they are discovered; the console channel is closed when
+// the scan finishes. The error channel then yields at most one error and is closed as well.
+func scanConsoleDirsChan(showHidden bool, prefixes sortPrefixes) (<-chan ConsoleDir, <-chan error) {
+	consoleCh := make(chan ConsoleDir, 16)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		defer close(consoleCh)
+
+		romsDir, _, _ := getBasePaths()
+		entries, err := os.ReadDir(romsDir)
+		if err != nil {
+			errCh <- fmt.Errorf("reading roms dir: %w", err)
+			return
+		}
+		coreMap := loadCoreMap()
+		for _, e := range entries {
+			if !e.IsDir() {
 				continue
 			}
-		} else {
-			// Always skip Mac/system artifacts regardless of showHidden.
-			if strings.HasPrefix(name, ".") || name == "map.txt" {
-				continue
+			if c, ok := consoleDirFromEntry(romsDir, e.Name(), showHidden, prefixes, coreMap); ok {
+				consoleCh <- c
 			}
 		}
+	}()
 
-		// Strip .disabled suffix for display/artwork lookup; mark entry as disabled.
-		isDisabled := strings.HasSuffix(name, ".disabled")
-		baseName := name
-		if isDisabled {
-			baseName = strings.TrimSuffix(name, ".disabled")
+	return consoleCh, errCh
+}
+
+// consoleDirFromEntry applies the scanConsoleDirs filtering rules to a single directory in
+// romsDir. coreMap is the loadCoreMap result for the scan. Returns false if the directory
+// is not a listable console folder.
+func consoleDirFromEntry(romsDir, name string, showHidden bool, prefixes sortPrefixes, coreMap map[string]string) (ConsoleDir, bool) {
+	fullPath := filepath.Join(romsDir, name)
+
+	if isShortcutFolder(fullPath, prefixes) {
+		return ConsoleDir{}, false
+	}
+
+	empty := isDirEmpty(fullPath)
+	hiddenOnly := !empty && !dirHasVisibleContent(fullPath)
+
+	if !showHidden {
+		if isHidden(name) {
+			return ConsoleDir{}, false
+		}
+		// Skip console dirs with no visible ROM content (empty or dotfiles only).
+		if empty {
+			log.Printf("scanConsoleDirs: skipping empty folder %s", name)
+			return ConsoleDir{}, false
+		}
+		if hiddenOnly {
+			log.Printf("scanConsoleDirs: skipping folder with hidden content only %s", name)
+			return ConsoleDir{}, false
+		}
+	} else {
+		// With showHidden: still exclude Mac dotfiles (dot-dirs without a (TAG)).
+		if isMacDotfile(name) || name == "map.txt" {
+			return ConsoleDir{}, false
 		}
+	}
 
-		if e.IsDir() {
-			dirPath := filepath.Join(consoleDir, name)
-			// Multi-disc: subfolder contains {baseName}.m3u playlist.
-			if _, err := os.Stat(filepath.Join(dirPath, baseName+".m3u")); err == nil {
-				roms = append(roms, ROMFile{
-					Name:        name,
-					Path:        dirPath,
-					Display:     baseName,
-					IsMultiDisc: true,
-					IsDisabled:  isDisabled,
-				})
-			} else if _, err := os.Stat(filepath.Join(dirPath, baseName+".cue")); err == nil {
-				// Single-disc CUE/BIN: subfolder contains {baseName}.cue.
-				roms = append(roms, ROMFile{
-					Name:        name,
-					Path:        dirPath,
-					Display:     baseName,
-					IsCueFolder: true,
-					IsDisabled:  isDisabled,
-				})
-			} else {
-				// Plain subfolder — recurse into it.
-				sub, err := scanROMs(dirPath, showHidden)
-				if err == nil {
-					roms = append(roms, sub...)
-				}
-			}
+	// For .disabled folders strip the suffix before extracting tag/display.
+	isDisabled := strings.HasSuffix(name, ".disabled")
+	baseName := name
+	if isDisabled {
+		baseName = strings.TrimSuffix(name, ".disabled")
+	}
+
+	tag := extractTag(baseName)
+	if tag == "" {
+		return ConsoleDir{}, false // no emu tag — skip
+	}
+	return ConsoleDir{
+		Name:       name,
+		Tag:        tag,
+		Path:       fullPath,
+		Display:    extractDisplayName(baseName),
+		IsDisabled: isDisabled,
+		IsEmpty:    empty,
+		HiddenOnly: hiddenOnly,
+		CorePath:   coreForTag(coreMap, tag),
+	}, true
+}
+
+// setConsoleDisabled renames c's folder to add or remove ".disabled" and returns the
+// console as it is after the rename. Fails if a folder with the new name already exists.
+func setConsoleDisabled(c ConsoleDir, disabled bool) (ConsoleDir, error) {
+	if c.IsDisabled == disabled {
+		return c, nil
+	}
+	newName := strings.TrimSuffix(c.Name, ".disabled")
+	if disabled {
+		newName += ".disabled"
+	}
+	newPath := filepath.Join(filepath.Dir(c.Path), newName)
+	if _, err := os.Stat(newPath); err == nil {
+		return c, fmt.Errorf("renaming %s: %s already exists", c.Name, newName)
+	}
+	if err := os.Rename(c.Path, newPath); err != nil {
+		return c, fmt.Errorf("renaming %s: %w", c.Name, err)
+	}
+	log.Printf("setConsoleDisabled: %s -> %s", c.Name, newName)
+	c.Name, c.Path, c.IsDisabled = newName, newPath, disabled
+	return c, nil
+}
+
+// countShortcutsIntoConsoleDir returns how many ROM shortcuts point into the console
+// folder name (see shortcutROMEntry).
+func countShortcutsIntoConsoleDir(name string, settings AppSettings) int {
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		logError("scanning shortcuts", err)
+		return 0
+	}
+	n := 0
+	for _, sc := range shortcuts {
+		_, entry, ok := shortcutROMEntry(sc)
+		if !ok {
 			continue
 		}
-		roms = append(roms, ROMFile{
-			Name:       name,
-			Path:       filepath.Join(consoleDir, name),
-			Display:    stripExtension(baseName),
-			IsDisabled: isDisabled,
-		})
+		if consoleDir, _, ok := splitM3UTarget(entry); ok && consoleDir == name {
+			n++
+		}
 	}
+	return n
+}
 
-	sort.Slice(roms, func(i, j int) bool {
-		return strings.ToLower(roms[i].Display) < strings.ToLower(roms[j].Display)
+// sortConsolesByOrder reorders consoles so those named in order (by Display) come first,
+// in that order, followed by the rest in their existing (alphabetical) order.
+func sortConsolesByOrder(consoles []ConsoleDir, order []string) {
+	if len(order) == 0 {
+		return
+	}
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		if _, dup := rank[name]; !dup {
+			rank[name] = i
+		}
+	}
+	sort.SliceStable(consoles, func(i, j int) bool {
+		ri, iok := rank[consoles[i].Display]
+		rj, jok := rank[consoles[j].Display]
+		switch {
+		case iok && jok:
+			return ri < rj
+		default:
+			return iok && !jok
+		}
 	})
-	log.Printf("scanROMs: dir=%s showHidden=%v roms=%d", consoleDir, showHidden, len(roms))
-	return roms, nil
 }
 
-// scanTools returns all tool .pak directories for the current platform.
-// When showHidden is true, .pak.disabled entries are also included.
-func scanTools(showHidden bool) ([]ToolPak, error) {
-	_, toolsDir, _ := getBasePaths()
-	entries, err := os.ReadDir(toolsDir)
+// findConsoleDir returns the console directory whose folder name is name.
+func findConsoleDir(name string, showHidden bool, prefixes sortPrefixes) (ConsoleDir, error) {
+	consoles, err := scanConsoleDirs(showHidden, prefixes)
 	if err != nil {
-		return nil, fmt.Errorf("reading tools dir: %w", err)
+		return ConsoleDir{}, err
+	}
+	for _, c := range consoles {
+		if c.Name == name {
+			return c, nil
+		}
 	}
+	return ConsoleDir{}, fmt.Errorf("console %q not found", name)
+}
 
-	var tools []ToolPak
-	for _, e := range entries {
-		if !e.IsDir() {
+// romCountCacheTTL is how long ROM counts are used before they are counted again.
+const romCountCacheTTL = 5 * time.Minute
+
+// romCountsFresh reports whether s.ROMCountCache can be used without rescanning.
+func (s AppSettings) romCountsFresh() bool {
+	return s.ROMCountCache != nil && time.Since(s.ROMCountCacheTime) < romCountCacheTTL
+}
+
+// romCounts holds the ROM count per console folder Name shown in the console picker,
+// counted at time at. The picker reads it (cachedROMCounts) and counts in the background
+// when it is empty or stale (startROMCountRefresh), so the picker itself never waits for a
+// full count.
+var romCounts struct {
+	sync.Mutex
+	counts   map[string]int
+	at       time.Time
+	counting bool
+}
+
+// consoleROMCounts scans every console and returns its number of ROMs, keyed by folder
+// Name. Consoles that can't be read are left out.
+func consoleROMCounts(consoles []ConsoleDir, settings AppSettings) map[string]int {
+	counts := make(map[string]int, len(consoles))
+	for _, c := range consoles {
+		roms, err := scanROMs(c.Path, settings.ShowHidden, settings.ScanDepth)
+		if err != nil {
+			log.Printf("consoleROMCounts: %s: %v", c.Name, err)
 			continue
 		}
-		name := e.Name()
-		if !showHidden && isHidden(name) {
-			continue
+		counts[c.Name] = len(roms)
+	}
+	log.Printf("consoleROMCounts: consoles=%d counted=%d", len(consoles), len(counts))
+	return counts
+}
+
+// cachedROMCounts returns a copy of the current ROM counts, and whether they are younger
+// than romCountCacheTTL and cover every console in consoles.
+func cachedROMCounts(consoles []ConsoleDir) (map[string]int, bool) {
+	romCounts.Lock()
+	defer romCounts.Unlock()
+	fresh := romCounts.counts != nil && time.Since(romCounts.at) < romCountCacheTTL
+	for _, c := range consoles {
+		if _, ok := romCounts.counts[c.Name]; !ok {
+			fresh = false
+			break
+		}
+	}
+	return maps.Clone(romCounts.counts), fresh
+}
+
+// storeROMCounts replaces the ROM counts with counts, counted at at.
+func storeROMCounts(counts map[string]int, at time.Time) {
+	romCounts.Lock()
+	romCounts.counts, romCounts.at = counts, at
+	romCounts.Unlock()
+}
+
+// primeROMCounts fills the ROM counts for the first time in a run. A fresh
+// AppSettings.ROMCountCache from the last run is used as is, with only consoles missing
+// from it counted; otherwise every console is counted and the result is saved as the new
+// ROMCountCache for the next run.
+func primeROMCounts(consoles []ConsoleDir, settings AppSettings) {
+	if settings.romCountsFresh() {
+		var missing []ConsoleDir
+		for _, c := range consoles {
+			if _, ok := settings.ROMCountCache[c.Name]; !ok {
+				missing = append(missing, c)
+			}
+		}
+		counts := maps.Clone(settings.ROMCountCache)
+		maps.Copy(counts, consoleROMCounts(missing, settings))
+		storeROMCounts(counts, settings.ROMCountCacheTime)
+		return
+	}
+
+	now := time.Now()
+	counts := consoleROMCounts(consoles, settings)
+	storeROMCounts(counts, now)
+	// Reload so settings changed in the UI meanwhile are kept.
+	s := loadSettings()
+	s.ROMCountCache, s.ROMCountCacheTime = counts, now
+	logError("saving ROM count cache", saveSettings(s))
+}
+
+// startROMCountRefresh counts the ROMs of consoles in the background unless a count is
+// already running. The first count of a run goes through primeROMCounts; a fresh
+// ROMCountCache from the last run is stored right away, so it can be shown meanwhile.
+// cachedROMCounts returns the result once it is done.
+func startROMCountRefresh(consoles []ConsoleDir, settings AppSettings) {
+	romCounts.Lock()
+	if romCounts.counting {
+		romCounts.Unlock()
+		return
+	}
+	romCounts.counting = true
+	primed := romCounts.counts != nil
+	romCounts.Unlock()
+
+	if !primed && settings.romCountsFresh() {
+		storeROMCounts(maps.Clone(settings.ROMCountCache), settings.ROMCountCacheTime)
+	}
+	consoles = slices.Clone(consoles) // the caller may reorder its slice meanwhile
+	go func() {
+		if primed {
+			now := time.Now()
+			storeROMCounts(consoleROMCounts(consoles, settings), now)
+		} else {
+			primeROMCounts(consoles, settings)
+		}
+		romCounts.Lock()
+		romCounts.counting = false
+		romCounts.Unlock()
+	}()
+}
+
+// findROM returns the ROM in console whose path relative to the console folder is name
+// (e.g. "Fire Red.gba" or "RPG/Fire Red.gba").
+func findROM(console ConsoleDir, name string, showHidden bool) (ROMFile, error) {
+	roms, err := scanROMs(console.Path, showHidden, -1)
+	if err != nil {
+		return ROMFile{}, err
+	}
+	want := filepath.Join(console.Path, filepath.FromSlash(name))
+	for _, r := range roms {
+		if r.Path == want {
+			return r, nil
+		}
+	}
+	return ROMFile{}, fmt.Errorf("rom %q not found in %s", name, console.Name)
+}
+
+// scanROMs returns all ROM files in a console directory.
+// When showHidden is false (default): hidden and .disabled entries are skipped.
+// When showHidden is true: .disabled entries are included with IsDisabled set; known
+// Mac artifacts (.DS_Store, map.txt, etc.) are always excluded.
+// depth limits how many levels of plain subfolders are searched: 0 = top level only,
+// negative = no limit. Multi-disc and CUE folders count as ROMs, not subfolders.
+func scanROMs(consoleDir string, showHidden bool, depth int) ([]ROMFile, error) {
+	roms, err := scanROMDir(consoleDir, "", showHidden, depth)
+	if err != nil {
+		return nil, err
+	}
+	sortROMs(roms, ROMSortAlpha)
+	log.Printf("scanROMs: dir=%s showHidden=%v depth=%d roms=%d", consoleDir, showHidden, depth, len(roms))
+	return roms, nil
+}
+
+// ROM list sort orders (AppSettings.ROMSortOrder).
+const (
+	ROMSortAlpha    = "alpha"     // by display name
+	ROMSortSizeDesc = "size-desc" // largest first
+	ROMSortSizeAsc  = "size-asc"  // smallest first
+	ROMSortDateDesc = "date-desc" // most recently modified first
+)
+
+// romSortOrders lists the sort orders in the order the ROM picker cycles through them.
+var romSortOrders = []string{ROMSortAlpha, ROMSortSizeDesc, ROMSortSizeAsc, ROMSortDateDesc}
+
+// sortROMs sorts roms in place by order (one of the ROMSort* constants; unknown values
+// sort alphabetically). Ties are broken by display name.
+func sortROMs(roms []ROMFile, order string) {
+	sort.SliceStable(roms, func(i, j int) bool {
+		a, b := roms[i], roms[j]
+		switch order {
+		case ROMSortSizeDesc:
+			if a.SizeBytes != b.SizeBytes {
+				return a.SizeBytes > b.SizeBytes
+			}
+		case ROMSortSizeAsc:
+			if a.SizeBytes != b.SizeBytes {
+				return a.SizeBytes < b.SizeBytes
+			}
+		case ROMSortDateDesc:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.After(b.ModTime)
+			}
+		}
+		return strings.ToLower(a.Display) < strings.ToLower(b.Display)
+	})
+}
+
+// scanROMDir lists the ROMs in consoleDir/subdir for scanROMs, recursing into plain
+// subfolders while depth allows.
+func scanROMDir(consoleDir, subdir string, showHidden bool, depth int) ([]ROMFile, error) {
+	dir := filepath.Join(consoleDir, subdir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading rom dir: %w", err)
+	}
+
+	// Core override sidecars, by the display name of the ROM they belong to.
+	sidecars := make(map[string]string)
+	for _, e := range entries {
+		if ext := filepath.Ext(e.Name()); !e.IsDir() && strings.EqualFold(ext, coreSidecarExt) {
+			sidecars[strings.TrimSuffix(e.Name(), ext)] = e.Name()
+		}
+	}
+	coreOverride := func(display string) string {
+		if name, ok := sidecars[display]; ok {
+			return readCoreOverride(filepath.Join(dir, name))
+		}
+		return ""
+	}
+
+	var roms []ROMFile
+	for _, e := range entries {
+		name := e.Name()
+
+		if !showHidden {
+			if isHidden(name) {
+				continue
+			}
+		} else {
+			// Always skip Mac/system artifacts regardless of showHidden.
+			if strings.HasPrefix(name, ".") || name == "map.txt" {
+				continue
+			}
+		}
+
+		if !e.IsDir() && strings.EqualFold(filepath.Ext(name), coreSidecarExt) {
+			continue
+		}
+
+		// Strip .disabled suffix for display/artwork lookup; mark entry as disabled.
+		isDisabled := strings.HasSuffix(name, ".disabled")
+		baseName := name
+		if isDisabled {
+			baseName = strings.TrimSuffix(name, ".disabled")
+		}
+
+		var modTime time.Time
+		var size int64
+		if info, err := e.Info(); err == nil {
+			modTime, size = info.ModTime(), info.Size()
+		}
+
+		if e.IsDir() {
+			dirPath := filepath.Join(dir, name)
+			// Multi-disc: subfolder contains {baseName}.m3u playlist.
+			if _, err := os.Stat(filepath.Join(dirPath, baseName+".m3u")); err == nil {
+				roms = append(roms, ROMFile{
+					Name:         name,
+					Path:         dirPath,
+					Display:      baseName,
+					Subdir:       subdir,
+					IsMultiDisc:  true,
+					IsDisabled:   isDisabled,
+					ModTime:      modTime,
+					CoreOverride: coreOverride(baseName),
+				})
+			} else if _, err := os.Stat(filepath.Join(dirPath, baseName+".cue")); err == nil {
+				// Single-disc CUE/BIN: subfolder contains {baseName}.cue.
+				roms = append(roms, ROMFile{
+					Name:         name,
+					Path:         dirPath,
+					Display:      baseName,
+					Subdir:       subdir,
+					IsCueFolder:  true,
+					IsDisabled:   isDisabled,
+					ModTime:      modTime,
+					CoreOverride: coreOverride(baseName),
+				})
+			} else if depth != 0 {
+				// Plain subfolder (e.g. "RPG/") — recurse into it.
+				sub, err := scanROMDir(consoleDir, filepath.Join(subdir, name), showHidden, depth-1)
+				if err == nil {
+					roms = append(roms, sub...)
+				}
+			}
+			continue
+		}
+		roms = append(roms, ROMFile{
+			Name:         name,
+			Path:         filepath.Join(dir, name),
+			Display:      stripExtension(baseName),
+			Subdir:       subdir,
+			IsArchive:    archiveExtensions[strings.ToLower(filepath.Ext(baseName))],
+			IsDisabled:   isDisabled,
+			SizeBytes:    size,
+			ModTime:      modTime,
+			CoreOverride: coreOverride(stripExtension(baseName)),
+		})
+	}
+	return roms, nil
+}
+
+// fillFolderSizes sets SizeBytes of the multi-disc and CUE folders in roms to the total of
+// the files inside. scanROMs leaves them at 0 since only the size sorts of the ROM picker
+// need them.
+func fillFolderSizes(roms []ROMFile) {
+	for i := range roms {
+		if roms[i].IsMultiDisc || roms[i].IsCueFolder {
+			roms[i].SizeBytes = dirSize(roms[i].Path)
+		}
+	}
+}
+
+// dirSize returns the total size of the regular files directly inside dir, e.g. the discs
+// of a multi-disc game.
+func dirSize(dir string) int64 {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	var total int64
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// readCoreOverride returns the trimmed contents of the .core sidecar at path, or "" if it
+// can't be read.
+func readCoreOverride(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// coreMapFile is the user core map in the app data dir; see loadCoreMap.
+const coreMapFile = "core_map.json"
+
+// defaultCoreMap maps console tags to the libretro cores NextUI's stock emulator paks use.
+var defaultCoreMap = map[string]string{
+	"FC":   "fceumm_libretro.so",
+	"GB":   "gambatte_libretro.so",
+	"GBC":  "gambatte_libretro.so",
+	"GBA":  "gpsp_libretro.so",
+	"MGBA": "mgba_libretro.so",
+	"MD":   "picodrive_libretro.so",
+	"PCE":  "mednafen_pce_fast_libretro.so",
+	"PKM":  "pokemini_libretro.so",
+	"PS":   "pcsx_rearmed_libretro.so",
+	"SFC":  "snes9x_libretro.so",
+}
+
+// loadCoreMap returns defaultCoreMap overlaid with core_map.json from the app data dir,
+// e.g. {"GBA": "mgba_libretro.so", "NGP": "/mnt/SDCARD/Cores/race_libretro.so"}. An empty
+// value removes a default mapping. A missing or unreadable file yields the defaults.
+func loadCoreMap() map[string]string {
+	m := maps.Clone(defaultCoreMap)
+	data, err := os.ReadFile(filepath.Join(getAppDataDir(), coreMapFile))
+	if err != nil {
+		return m
+	}
+	var user map[string]string
+	if err := json.Unmarshal(data, &user); err != nil {
+		log.Printf("loadCoreMap: parse error: %v", err)
+		return m
+	}
+	for tag, core := range user {
+		if core == "" {
+			delete(m, tag)
+		} else {
+			m[tag] = core
+		}
+	}
+	return m
+}
+
+// coreForTag returns the core path for a console tag from coreMap (see loadCoreMap), as
+// resolved by resolveCorePath. Returns "" for unmapped tags.
+func coreForTag(coreMap map[string]string, tag string) string {
+	return resolveCorePath(coreMap[tag])
+}
+
+// resolveCorePath turns a core from the core map or a .core sidecar into a path. A bare
+// core name such as "mgba" gets the "_libretro.so" suffix, and file names are resolved
+// against the platform's cores dir (.system/<platform>/cores).
+func resolveCorePath(core string) string {
+	if core == "" || filepath.IsAbs(core) {
+		return core
+	}
+	if !strings.HasSuffix(core, ".so") {
+		core += "_libretro.so"
+	}
+	return filepath.Join(getSDCardRoot(), ".system", platform.dirName(), "cores", core)
+}
+
+// getSavesDir returns the Saves directory on the card from getSDCardRoot.
+func getSavesDir() string {
+	return filepath.Join(getSDCardRoot(), "Saves")
+}
+
+// scanSaves returns the save files for the console whose ROM directory is consoleDir.
+// NextUI keeps saves in Saves/<TAG>/, so the tag is taken from the console folder name
+// (a .disabled suffix is ignored). A missing Saves/<TAG>/ directory yields no saves.
+func scanSaves(consoleDir string) ([]SaveFile, error) {
+	tag := extractTag(strings.TrimSuffix(filepath.Base(consoleDir), ".disabled"))
+	if tag == "" {
+		return nil, fmt.Errorf("no tag in console dir %q", consoleDir)
+	}
+	dir := filepath.Join(getSavesDir(), tag)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading saves dir: %w", err)
+	}
+
+	var saves []SaveFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(name))
+		if !saveExtensions[ext] {
+			continue
+		}
+		saves = append(saves, SaveFile{
+			Name:       name,
+			Path:       filepath.Join(dir, name),
+			ROMDisplay: strings.TrimSuffix(name, filepath.Ext(name)),
+			Format:     strings.TrimPrefix(ext, "."),
+		})
+	}
+
+	sort.Slice(saves, func(i, j int) bool {
+		return strings.ToLower(saves[i].ROMDisplay) < strings.ToLower(saves[j].ROMDisplay)
+	})
+	log.Printf("scanSaves: dir=%s saves=%d", dir, len(saves))
+	return saves, nil
+}
+
+// crossReferenceSaves sets HasSave on every ROM that has a matching save file.
+// A save matches when its ROMDisplay equals the ROM's file name ("Game.gba.sav")
+// or its display name ("Game.sav").
+func crossReferenceSaves(roms []ROMFile, saves []SaveFile) {
+	names := make(map[string]bool, len(saves))
+	for _, sv := range saves {
+		names[strings.ToLower(sv.ROMDisplay)] = true
+	}
+	for i := range roms {
+		base := strings.TrimSuffix(roms[i].Name, ".disabled")
+		roms[i].HasSave = names[strings.ToLower(base)] || names[strings.ToLower(roms[i].Display)]
+	}
+}
+
+// getSaveStatesDir returns the folder holding NextUI's per-emulator state folders.
+func getSaveStatesDir() string {
+	return filepath.Join(getSDCardRoot(), ".userdata", platform.dirName())
+}
+
+// scanSaveStates returns rom's save states, newest first. NextUI keeps them as
+// <rom file>.st<slot> in one folder per emulator pak, e.g. "GBA-gpsp", so every folder
+// matching tag (see emuMatchesTag) is searched. No states yields an empty slice.
+func scanSaveStates(tag string, rom ROMFile) ([]SaveState, error) {
+	dir := getSaveStatesDir()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading userdata dir: %w", err)
+	}
+
+	prefix := strings.TrimSuffix(rom.Name, ".disabled") + ".st"
+	var states []SaveState
+	for _, e := range entries {
+		if !e.IsDir() || !emuMatchesTag(e.Name(), tag) {
+			continue
+		}
+		emuDir := filepath.Join(dir, e.Name())
+		files, err := os.ReadDir(emuDir)
+		if err != nil {
+			log.Printf("scanSaveStates: %v", err)
+			continue
+		}
+		for _, f := range files {
+			digits, ok := strings.CutPrefix(f.Name(), prefix)
+			if !ok || f.IsDir() {
+				continue
+			}
+			slot, err := strconv.Atoi(digits)
+			if err != nil {
+				continue
+			}
+			info, err := f.Info()
+			if err != nil {
+				continue
+			}
+			states = append(states, SaveState{Path: filepath.Join(emuDir, f.Name()), Slot: slot, ModTime: info.ModTime()})
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].ModTime.After(states[j].ModTime)
+	})
+	log.Printf("scanSaveStates: tag=%s rom=%s states=%d", tag, rom.Name, len(states))
+	return states, nil
+}
+
+// scanTools returns all tool .pak directories for the current platform.
+// When showHidden is true, .pak.disabled entries are also included. When deep is true,
+// paks in subfolders are included too, grouped after the top-level ones by folder.
+func scanTools(showHidden, deep bool) ([]ToolPak, error) {
+	_, toolsDir, _ := getBasePaths()
+	tools, err := scanToolDir(toolsDir, "", showHidden, deep)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Subdir != tools[j].Subdir {
+			return strings.ToLower(tools[i].Subdir) < strings.ToLower(tools[j].Subdir)
+		}
+		return strings.ToLower(tools[i].Display) < strings.ToLower(tools[j].Display)
+	})
+	log.Printf("scanTools: dir=%s deep=%v tools=%d", toolsDir, deep, len(tools))
+	return tools, nil
+}
+
+// scanToolDir collects the paks in toolsDir/subdir for scanTools. With deep set, folders
+// that are not paks are searched too (e.g. Tools/tg5040/Emulation/DuckStation.pak).
+func scanToolDir(toolsDir, subdir string, showHidden, deep bool) ([]ToolPak, error) {
+	dir := filepath.Join(toolsDir, subdir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading tools dir: %w", err)
+	}
+
+	var tools []ToolPak
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !showHidden && isHidden(name) {
+			continue
 		}
 		if showHidden && strings.HasPrefix(name, ".") {
 			continue // always skip dot-dirs in Tools
 		}
 
+		if isBridgePak(name) {
+			log.Printf("scanToolDir: skipping bridge pak %s", filepath.Join(subdir, name))
+			continue
+		}
+
 		// Accept both .pak and .pak.disabled
 		isDisabled := strings.HasSuffix(name, ".pak.disabled")
 		if !strings.HasSuffix(name, ".pak") && !isDisabled {
+			if deep {
+				sub, err := scanToolDir(toolsDir, filepath.Join(subdir, name), showHidden, deep)
+				if err == nil {
+					tools = append(tools, sub...)
+				}
+			}
+			continue
+		}
+		baseName := strings.TrimSuffix(name, ".pak")
+		if isDisabled {
+			baseName = strings.TrimSuffix(strings.TrimSuffix(name, ".disabled"), ".pak")
+		}
+		display := baseName
+		if isDisabled {
+			display += "  [disabled]"
+		}
+		path := filepath.Join(dir, name)
+		meta, _ := readPakMeta(path)
+		tools = append(tools, ToolPak{
+			Name:             baseName,
+			Path:             path,
+			Display:          display,
+			Subdir:           subdir,
+			Description:      strings.TrimSpace(meta.Description),
+			Version:          strings.TrimSpace(meta.Version),
+			IsShortcutTarget: !meta.NoShortcut,
+		})
+	}
+	return tools, nil
+}
+
+// scanSystemTools returns the built-in .pak directories under .system/<platform>/ with
+// IsSystem set. A missing system directory yields no tools.
+func scanSystemTools() ([]ToolPak, error) {
+	romsDir, _, _ := getBasePaths()
+	systemDir := filepath.Join(filepath.Dir(romsDir), ".system", platform.dirName())
+	entries, err := os.ReadDir(systemDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading system dir: %w", err)
+	}
+
+	var tools []ToolPak
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || isHidden(name) || !strings.HasSuffix(name, ".pak") {
+			continue
+		}
+		if isBridgePak(name) {
+			log.Printf("scanSystemTools: skipping bridge pak %s", name)
+			continue
+		}
+		baseName := strings.TrimSuffix(name, ".pak")
+		path := filepath.Join(systemDir, name)
+		meta, _ := readPakMeta(path)
+		tools = append(tools, ToolPak{
+			Name:             baseName,
+			Path:             path,
+			Display:          baseName,
+			IsSystem:         true,
+			Description:      strings.TrimSpace(meta.Description),
+			Version:          strings.TrimSpace(meta.Version),
+			IsShortcutTarget: !meta.NoShortcut,
+		})
+	}
+	log.Printf("scanSystemTools: dir=%s tools=%d", systemDir, len(tools))
+	return tools, nil
+}
+
+// EmuPak is an installed emulator pak, as found by scanEmulatorsForTag.
+type EmuPak struct {
+	Name     string // pak base name, e.g. "GBA" or "GBA-mgba"
+	Path     string // full path to the .pak directory
+	IsSystem bool   // true for NextUI's built-in paks under .system/<platform>/
+}
+
+// systemEmuSubdirs are the folders under .system/<platform>/ holding built-in emulator
+// paks: "paks/Emus" is the NextUI/MinUI layout; "emulators" is checked as well.
+var systemEmuSubdirs = []string{filepath.Join("paks", "Emus"), "emulators"}
+
+// scanEmulatorsForTag returns the emulator paks that can launch ROMs tagged tag: user paks
+// in Emus/<platform>/ first, then built-in ones, each sorted by name. A pak matches when
+// its name is the tag or starts with the tag and a separator (e.g. "GBA-mgba.pak"), so
+// "GB" does not match "GBA.pak". SHORTCUT.pak is never listed.
+func scanEmulatorsForTag(tag string) ([]EmuPak, error) {
+	romsDir, _, emusDir := getBasePaths()
+	user, err := scanEmuDir(emusDir, tag, false)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading emus dir: %w", err)
+	}
+	paks := user
+	systemDir := filepath.Join(filepath.Dir(romsDir), ".system", platform.dirName())
+	for _, sub := range systemEmuSubdirs {
+		system, err := scanEmuDir(filepath.Join(systemDir, sub), tag, true)
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("scanEmulatorsForTag: %v", err)
+		}
+		paks = append(paks, system...)
+	}
+	log.Printf("scanEmulatorsForTag: tag=%s paks=%d", tag, len(paks))
+	return paks, nil
+}
+
+// scanEmuDir lists the .pak directories in dir matching tag for scanEmulatorsForTag.
+func scanEmuDir(dir, tag string, isSystem bool) ([]EmuPak, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paks []EmuPak
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || isHidden(name) || !strings.HasSuffix(name, ".pak") {
+			continue
+		}
+		base := strings.TrimSuffix(name, ".pak")
+		if isToolTag(base) || !emuMatchesTag(base, tag) {
+			continue
+		}
+		paks = append(paks, EmuPak{Name: base, Path: filepath.Join(dir, name), IsSystem: isSystem})
+	}
+	sort.Slice(paks, func(i, j int) bool {
+		return strings.ToLower(paks[i].Name) < strings.ToLower(paks[j].Name)
+	})
+	return paks, nil
+}
+
+// takesSaveState reports whether the pak's launch.sh reads a second argument, which is how
+// save state shortcuts pass the state to load. NextUI's stock paks only take the ROM.
+func (p EmuPak) takesSaveState() bool {
+	data, err := os.ReadFile(filepath.Join(p.Path, "launch.sh"))
+	if err != nil {
+		return false
+	}
+	script := string(data)
+	return strings.Contains(script, "$2") || strings.Contains(script, "${2")
+}
+
+// saveStateEmuPak returns the emulator pak a save state shortcut for tag launches: the
+// first from scanEmulatorsForTag whose launch.sh takes the state (see takesSaveState),
+// preferring the one named exactly tag. ok is false if none does.
+func saveStateEmuPak(tag string) (pak EmuPak, ok bool, err error) {
+	paks, err := scanEmulatorsForTag(tag)
+	if err != nil {
+		return EmuPak{}, false, err
+	}
+	paks = slices.DeleteFunc(paks, func(p EmuPak) bool { return !p.takesSaveState() })
+	if len(paks) == 0 {
+		return EmuPak{}, false, nil
+	}
+	if i := slices.IndexFunc(paks, func(p EmuPak) bool { return strings.EqualFold(p.Name, tag) }); i >= 0 {
+		return paks[i], true, nil
+	}
+	return paks[0], true, nil
+}
+
+// emuMatchesTag reports whether an emulator pak named base serves tag: an exact match
+// (case-insensitive), or the tag followed by '-', '_' or a space.
+func emuMatchesTag(base, tag string) bool {
+	if len(base) < len(tag) || !strings.EqualFold(base[:len(tag)], tag) {
+		return false
+	}
+	return len(base) == len(tag) || strings.ContainsRune("-_ ", rune(base[len(tag)]))
+}
+
+// scanShortcuts returns all existing shortcuts, recognised by the sort prefixes of settings.
+func scanShortcuts(settings AppSettings) ([]Shortcut, error) {
+	romsDir, _, _ := getBasePaths()
+	entries, err := os.ReadDir(romsDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading roms dir: %w", err)
+	}
+	prefixes := settings.sortPrefixes()
+
+	var shortcuts []Shortcut
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		fullPath := filepath.Join(romsDir, e.Name())
+		if !isShortcutFolder(fullPath, prefixes) {
+			continue
+		}
+		name := e.Name()
+		pos, prefix := prefixes.match(name)
+		tag := extractTag(name)
+		isSaveState := isToolTag(tag) && fileExists(filepath.Join(fullPath, shortcutROMFile))
+		isTool := isToolTag(tag) && !isSaveState
+
+		// Read display name from marker file if present; fall back to extracting from folder name.
+		meta, metaErr := readAndValidateShortcutMarker(fullPath, prefixes)
+		display := meta.DisplayName
+		nameMismatch := metaErr == nil && !folderNameMatches(name, prefix, display)
+
+		sc := Shortcut{
+			Name:         name,
+			Tag:          tag,
+			Display:      display,
+			Path:         fullPath,
+			IsTool:       isTool,
+			IsSaveState:  isSaveState,
+			AccessCount:  meta.AccessCount,
+			LastAccessed: meta.LastAccessed,
+
+			ConsoleDirName: meta.ConsoleDirName,
+			Position:       pos,
+
+			TargetMissingSince: meta.TargetMissingSince,
+
+			CreatedAt:      meta.CreatedAt,
+			Description:    meta.Description,
+			Version:        meta.Version,
+			IsNameMismatch: nameMismatch,
+		}
+		sc.MediaDir = filepath.Join(fullPath, settings.mediaSubdir())
+
+		// Resolve target
+		switch {
+		case isSaveState:
+			// "target" holds the emulator pak; the ROM is the real target.
+			data, err := os.ReadFile(filepath.Join(sc.Path, shortcutROMFile))
+			if err == nil {
+				sc.TargetPath = strings.TrimSpace(string(data))
+				if !filepath.IsAbs(sc.TargetPath) {
+					sc.TargetPath = filepath.Join(sc.Path, sc.TargetPath)
+				}
+			}
+		case isTool:
+			targetFile := filepath.Join(sc.Path, "target")
+			data, err := os.ReadFile(targetFile)
+			if err == nil {
+				sc.TargetPath = strings.TrimSpace(string(data))
+			}
+			sc.Env = readToolEnv(sc.Path)
+		default:
+			m3uFile := filepath.Join(sc.Path, name+".m3u")
+			data, err := os.ReadFile(m3uFile)
+			if err == nil {
+				relPath := normalizeM3ULine(string(data))
+				sc.TargetPath = filepath.Join(sc.Path, relPath)
+			}
+		}
+
+		shortcuts = append(shortcuts, sc)
+	}
+
+	sort.Slice(shortcuts, func(i, j int) bool {
+		return strings.ToLower(shortcuts[i].Display) < strings.ToLower(shortcuts[j].Display)
+	})
+	log.Printf("scanShortcuts: dir=%s shortcuts=%d", romsDir, len(shortcuts))
+	return shortcuts, nil
+}
+
+// ── Bulk ROM shortcuts ───────────────────────────────────────
+
+// autoCreateShortcutsForConsole creates a shortcut at pos for every ROM in console, named
+// with the display name template. ROMs that already have a shortcut, and disabled ROMs
+// (which would not launch), are skipped. If progress is non-nil it receives an update per
+// ROM and is closed on return.
+func autoCreateShortcutsForConsole(console ConsoleDir, pos ShortcutPosition, settings AppSettings, progress chan<- MediaProgress) (created, skipped int, err error) {
+	if progress != nil {
+		defer close(progress)
+	}
+	roms, err := scanROMs(console.Path, settings.ShowHidden, settings.ScanDepth)
+	if err != nil {
+		return 0, 0, err
+	}
+	coreMap := loadCoreMap()
+	for i, rom := range roms {
+		if progress != nil {
+			progress <- MediaProgress{Completed: i, Total: len(roms)}
+		}
+		displayName := applyDisplayNameTemplate(settings.DisplayNameTemplate, rom, console)
+		if rom.IsDisabled || shortcutExists(displayName, console.Tag, settings.sortPrefixes()) {
+			skipped++
+			continue
+		}
+		if _, err := createROMShortcut(displayName, console.Tag, console.Name, rom, pos, coreMap, settings); err != nil {
+			log.Printf("autoCreateShortcutsForConsole: %s: %v", rom.Name, err)
+			skipped++
+			continue
+		}
+		created++
+	}
+	log.Printf("autoCreateShortcutsForConsole: console=%s created=%d skipped=%d", console.Name, created, skipped)
+	if created > 0 {
+		logError("updating shortcut registry", updateShortcutRegistry(settings))
+	}
+	return created, skipped, nil
+}
+
+// ── RetroArch playlist import ────────────────────────────────
+
+// lplPlaylist is the subset of RetroArch's JSON .lpl playlist format used for import.
+type lplPlaylist struct {
+	Items []lplEntry `json:"items"`
+}
+
+type lplEntry struct {
+	Path     string `json:"path"`
+	Label    string `json:"label"`
+	CorePath string `json:"core_path"`
+}
+
+// findLPLFiles returns the .lpl playlists in the SD card root, Playlists/ and
+// RetroArch/playlists/, sorted by path.
+func findLPLFiles() []string {
+	romsDir, _, _ := getBasePaths()
+	root := filepath.Dir(romsDir)
+	var files []string
+	for _, dir := range []string{root, filepath.Join(root, "Playlists"), filepath.Join(root, "RetroArch", "playlists")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() && !isMacDotfile(e.Name()) && strings.EqualFold(filepath.Ext(e.Name()), ".lpl") {
+				files = append(files, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// importFromLPL creates a bottom-positioned ROM shortcut for every entry in a RetroArch
+// playlist. Each entry's path is resolved to a console via its Roms/<console>/ component,
+// falling back to a search of every console for a ROM with the same file name. Entries
+// that cannot be resolved, or whose shortcut already exists, are skipped.
+func importFromLPL(lplPath string, settings AppSettings) (imported, skipped int, err error) {
+	data, err := os.ReadFile(lplPath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading playlist: %w", err)
+	}
+	var pl lplPlaylist
+	if err := json.Unmarshal(data, &pl); err != nil {
+		return 0, 0, fmt.Errorf("parsing playlist: %w", err)
+	}
+
+	consoles, err := scanConsoleDirs(settings.ShowHidden, settings.sortPrefixes())
+	if err != nil {
+		return 0, 0, err
+	}
+	coreMap := loadCoreMap()
+	romCache := make(map[string][]ROMFile) // console path → ROMs, scanned on demand
+	consoleROMs := func(c ConsoleDir) []ROMFile {
+		roms, ok := romCache[c.Path]
+		if !ok {
+			roms, _ = scanROMs(c.Path, settings.ShowHidden, settings.ScanDepth)
+			romCache[c.Path] = roms
+		}
+		return roms
+	}
+
+	for _, item := range pl.Items {
+		console, rom, ok := resolveLPLEntry(item, consoles, consoleROMs)
+		if !ok {
+			log.Printf("importFromLPL: skipping %q: no matching ROM for %s (core %s)", item.Label, item.Path, item.CorePath)
+			skipped++
+			continue
+		}
+		displayName := item.Label
+		if displayName == "" {
+			displayName = rom.Display
+		}
+		if shortcutExists(displayName, console.Tag, settings.sortPrefixes()) {
+			log.Printf("importFromLPL: skipping %q: shortcut already exists", displayName)
+			skipped++
+			continue
+		}
+		if _, err := createROMShortcut(displayName, console.Tag, console.Name, rom, ShortcutPositionBottom, coreMap, settings); err != nil {
+			log.Printf("importFromLPL: skipping %q: %v", displayName, err)
+			skipped++
+			continue
+		}
+		imported++
+	}
+	log.Printf("importFromLPL: %s: imported=%d skipped=%d", lplPath, imported, skipped)
+	if imported > 0 {
+		logError("updating shortcut registry", updateShortcutRegistry(settings))
+	}
+	return imported, skipped, nil
+}
+
+// resolveLPLEntry finds the console and ROM an .lpl entry refers to.
+func resolveLPLEntry(item lplEntry, consoles []ConsoleDir, consoleROMs func(ConsoleDir) []ROMFile) (ConsoleDir, ROMFile, bool) {
+	p := filepath.ToSlash(item.Path)
+	// Strip an archive member suffix ("game.zip#game.gba").
+	if i := strings.Index(p, "#"); i >= 0 {
+		p = p[:i]
+	}
+	if p == "" {
+		return ConsoleDir{}, ROMFile{}, false
+	}
+
+	// matchROM returns the ROM whose path is the file itself, or the multi-disc /
+	// CUE folder that contains it.
+	matchROM := func(c ConsoleDir, roms []ROMFile, rel string) (ROMFile, bool) {
+		for _, r := range roms {
+			relROM, _ := filepath.Rel(c.Path, r.Path)
+			relROM = filepath.ToSlash(relROM)
+			if relROM == rel || ((r.IsMultiDisc || r.IsCueFolder) && relROM == path.Dir(rel)) {
+				return r, true
+			}
+		}
+		return ROMFile{}, false
+	}
+
+	// Roms/<console>/<rel> — the usual layout for playlists made on a NextUI card.
+	if i := strings.Index(p, "/Roms/"); i >= 0 {
+		if consoleName, rel, found := strings.Cut(p[i+len("/Roms/"):], "/"); found {
+			for _, c := range consoles {
+				if c.Name == consoleName {
+					if r, ok := matchROM(c, consoleROMs(c), rel); ok {
+						return c, r, true
+					}
+				}
+			}
+		}
+	}
+
+	// Playlists from another device: fall back to the file name (or the name of the
+	// multi-disc / CUE folder containing it) in any console.
+	base := path.Base(p)
+	for _, c := range consoles {
+		for _, r := range consoleROMs(c) {
+			if filepath.Base(r.Path) == base || ((r.IsMultiDisc || r.IsCueFolder) && filepath.Base(r.Path) == path.Base(path.Dir(p))) {
+				return c, r, true
+			}
+		}
+	}
+	return ConsoleDir{}, ROMFile{}, false
+}
+
+// ── Shortcut filtering ───────────────────────────────────────
+
+// filterShortcutsByTag returns the shortcuts whose tag equals tag (case-insensitive).
+func filterShortcutsByTag(shortcuts []Shortcut, tag string) []Shortcut {
+	var out []Shortcut
+	for _, sc := range shortcuts {
+		if strings.EqualFold(sc.Tag, tag) {
+			out = append(out, sc)
+		}
+	}
+	return out
+}
+
+// filterShortcutsByPosition returns the shortcuts sorted into the given position tier.
+func filterShortcutsByPosition(shortcuts []Shortcut, pos ShortcutPosition) []Shortcut {
+	var out []Shortcut
+	for _, sc := range shortcuts {
+		if sc.Position == pos {
+			out = append(out, sc)
+		}
+	}
+	return out
+}
+
+// searchShortcuts returns the shortcuts whose display name contains query (case-insensitive).
+func searchShortcuts(shortcuts []Shortcut, query string) []Shortcut {
+	q := strings.ToLower(query)
+	var out []Shortcut
+	for _, sc := range shortcuts {
+		if strings.Contains(strings.ToLower(sc.Display), q) {
+			out = append(out, sc)
+		}
+	}
+	return out
+}
+
+// ── Pinned tools ─────────────────────────────────────────────
+
+// isToolPinned reports whether the pak base name (e.g. "Clock.pak") is in pinned.
+func isToolPinned(pinned []string, name string) bool {
+	for _, p := range pinned {
+		if strings.EqualFold(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// togglePinnedTool adds name to pinned, or removes it if already present.
+func togglePinnedTool(pinned []string, name string) []string {
+	if !isToolPinned(pinned, name) {
+		return append(pinned, name)
+	}
+	out := pinned[:0:0]
+	for _, p := range pinned {
+		if !strings.EqualFold(p, name) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// splitPinnedTools partitions tools into pinned and unpinned, keeping the original
+// (alphabetical) order within each group.
+func splitPinnedTools(tools []ToolPak, pinnedNames []string) (pinned, rest []ToolPak) {
+	for _, t := range tools {
+		if isToolPinned(pinnedNames, filepath.Base(t.Path)) {
+			pinned = append(pinned, t)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	return pinned, rest
+}
+
+// pickToolListIndex returns the row of the tool at toolPath in pickTool's list, which
+// shows pinned, then a divider row when both groups are non-empty, then rest.
+func pickToolListIndex(pinned, rest []ToolPak, toolPath string) int {
+	for i, t := range pinned {
+		if t.Path == toolPath {
+			return i
+		}
+	}
+	offset := len(pinned)
+	if len(pinned) > 0 {
+		offset++ // divider
+	}
+	for i, t := range rest {
+		if t.Path == toolPath {
+			return offset + i
+		}
+	}
+	return 0
+}
+
+// ── Shortcut creation / removal ──────────────────────────────
+
+// createROMShortcut creates a ROM shortcut folder with m3u and a .shortcut marker.
+// For multi-disc ROMs the m3u points to the playlist inside the game subfolder.
+// For CUE folder ROMs the m3u points to the .cue file inside the game subfolder.
+// coreMap is the loadCoreMap result, loaded once per operation by the caller.
+// artErr is set when the shortcut was created but its bg.png could not be generated.
+func createROMShortcut(displayName, tag, consoleDirName string, rom ROMFile, pos ShortcutPosition, coreMap map[string]string, settings AppSettings) (artErr, err error) {
+	romsDir, _, _ := getBasePaths()
+	folderName, err := resolveNameConflict(romsDir, buildFolderName(pos, displayName, tag, settings.sortPrefixes()))
+	if err != nil {
+		return nil, fmt.Errorf("choosing folder name: %w", err)
+	}
+	folderPath := filepath.Join(romsDir, folderName)
+	log.Printf("createROMShortcut: name=%s tag=%s rom=%s pos=%d multiDisc=%v core=%q", displayName, tag, rom.Name, pos, rom.IsMultiDisc, rom.CoreOverride)
+
+	if err := os.MkdirAll(folderPath, 0755); err != nil {
+		return nil, fmt.Errorf("creating shortcut dir: %w", err)
+	}
+
+	m3uPath := filepath.Join(folderPath, folderName+".m3u")
+	if err := os.WriteFile(m3uPath, []byte(romM3UEntry(romsDir, rom)), 0644); err != nil {
+		return nil, fmt.Errorf("writing m3u: %w", err)
+	}
+
+	// A .core sidecar replaces the tag's core; core_path is the one place either is recorded.
+	corePath := coreForTag(coreMap, tag)
+	if rom.CoreOverride != "" {
+		corePath = resolveCorePath(rom.CoreOverride)
+	}
+	meta := ShortcutMeta{DisplayName: displayName, ConsoleDirName: consoleDirName, CorePath: corePath, CreatedAt: time.Now()}
+	if err := writeShortcutMeta(folderPath, meta); err != nil {
+		log.Printf("createROMShortcut: warning: could not write marker: %v", err)
+	}
+
+	if settings.CopyArtwork {
+		artworkSrc := filepath.Join(filepath.Dir(rom.Path), settings.mediaSubdir(), rom.Display+".png")
+		useGlobalBg, forceBlack := settings.artworkBgParams()
+		candidates := artSearchCandidates(artworkSrc, consoleDirName, rom.Display, settings)
+		if _, err := generateArtworkBg(candidates, folderPath, useGlobalBg, forceBlack, settings); err != nil {
+			log.Printf("createROMShortcut: warning: artwork: %v", err)
+			artErr = err
+		}
+	}
+
+	log.Printf("createROMShortcut: created folder=%s", folderPath)
+	return artErr, nil
+}
+
+// createSaveStateShortcut creates a shortcut that boots rom and loads the save state at
+// saveStatePath. It is launched through SHORTCUT.pak like a tool shortcut: "target" holds
+// the emulator pak from saveStateEmuPak, and the folder also gets shortcutROMFile and a
+// copy of the state as shortcutSaveStateFile, so later saves to the same slot don't change
+// it. If a file can't be written the folder is removed again. artErr is set as for
+// createROMShortcut.
+func createSaveStateShortcut(displayName, tag, consoleDirName string, rom ROMFile, saveStatePath string, pos ShortcutPosition, settings AppSettings) (artErr, err error) {
+	emu, ok, err := saveStateEmuPak(tag)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no emulator pak for %s loads a save state passed at launch", tag)
+	}
+	state, err := os.ReadFile(saveStatePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading save state: %w", err)
+	}
+
+	romsDir, _, _ := getBasePaths()
+	folderName, err := resolveNameConflict(romsDir, buildFolderName(pos, displayName, bridgeEmuTag, settings.sortPrefixes()))
+	if err != nil {
+		return nil, fmt.Errorf("choosing folder name: %w", err)
+	}
+	folderPath := filepath.Join(romsDir, folderName)
+	log.Printf("createSaveStateShortcut: name=%s rom=%s state=%s emu=%s pos=%d", displayName, rom.Name, saveStatePath, emu.Path, pos)
+
+	if err := os.MkdirAll(folderPath, 0755); err != nil {
+		return nil, fmt.Errorf("creating shortcut dir: %w", err)
+	}
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"target", []byte(emu.Path)},
+		{folderName + ".m3u", []byte("target")},
+		{shortcutROMFile, []byte(romM3UEntry(romsDir, rom))},
+		{shortcutSaveStateFile, state},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(folderPath, f.name), f.data, 0644); err != nil {
+			// NextUI would list the half-written folder.
+			logError("createSaveStateShortcut: removing "+folderPath, os.RemoveAll(folderPath))
+			return nil, fmt.Errorf("writing %s: %w", f.name, err)
+		}
+	}
+
+	meta := ShortcutMeta{DisplayName: displayName, ConsoleDirName: consoleDirName, CreatedAt: time.Now()}
+	if err := writeShortcutMeta(folderPath, meta); err != nil {
+		log.Printf("createSaveStateShortcut: warning: could not write marker: %v", err)
+	}
+
+	if settings.CopyArtwork {
+		artworkSrc := filepath.Join(filepath.Dir(rom.Path), settings.mediaSubdir(), rom.Display+".png")
+		useGlobalBg, forceBlack := settings.artworkBgParams()
+		candidates := artSearchCandidates(artworkSrc, consoleDirName, rom.Display, settings)
+		if _, err := generateArtworkBg(candidates, folderPath, useGlobalBg, forceBlack, settings); err != nil {
+			log.Printf("createSaveStateShortcut: warning: artwork: %v", err)
+			artErr = err
+		}
+	}
+
+	log.Printf("createSaveStateShortcut: created folder=%s", folderPath)
+	return artErr, nil
+}
+
+// createToolShortcut creates a tool shortcut folder with m3u, target, and a .shortcut marker.
+// When env is non-empty the variables are written to env.json/env.sh for the bridge emu.
+// artErr is set as for createROMShortcut.
+func createToolShortcut(displayName, pakPath string, env map[string]string, pos ShortcutPosition, settings AppSettings) (artErr, err error) {
+	romsDir, toolsDir, _ := getBasePaths()
+	tag := toolShortcutTag(pakPath)
+	folderName, err := resolveNameConflict(romsDir, buildFolderName(pos, displayName, tag, settings.sortPrefixes()))
+	if err != nil {
+		return nil, fmt.Errorf("choosing folder name: %w", err)
+	}
+	folderPath := filepath.Join(romsDir, folderName)
+	log.Printf("createToolShortcut: name=%s pak=%s tag=%s pos=%d env=%d", displayName, pakPath, tag, pos, len(env))
+
+	if err := os.MkdirAll(folderPath, 0755); err != nil {
+		return nil, fmt.Errorf("creating shortcut dir: %w", err)
+	}
+
+	// Write target file containing the .pak path
+	targetPath := filepath.Join(folderPath, "target")
+	if err := os.WriteFile(targetPath, []byte(pakPath), 0644); err != nil {
+		return nil, fmt.Errorf("writing target: %w", err)
+	}
+
+	// Write m3u that points to "target"
+	m3uPath := filepath.Join(folderPath, folderName+".m3u")
+	if err := os.WriteFile(m3uPath, []byte("target"), 0644); err != nil {
+		return nil, fmt.Errorf("writing m3u: %w", err)
+	}
+
+	if missing := checkToolDependencies(ToolPak{Path: pakPath}); len(missing) > 0 {
+		log.Printf("createToolShortcut: warning: missing dependencies: %s", strings.Join(missing, ", "))
+	}
+
+	if len(env) > 0 {
+		if err := writeToolEnv(folderPath, env); err != nil {
+			return nil, fmt.Errorf("writing env: %w", err)
+		}
+	}
+
+	pakMeta, _ := readPakMeta(pakPath)
+	meta := ShortcutMeta{
+		DisplayName: displayName,
+		CreatedAt:   time.Now(),
+		Description: strings.TrimSpace(pakMeta.Description),
+		Version:     strings.TrimSpace(pakMeta.Version),
+	}
+	if err := writeShortcutMeta(folderPath, meta); err != nil {
+		log.Printf("createToolShortcut: warning: could not write marker: %v", err)
+	}
+
+	if settings.CopyArtwork {
+		artworkSrc := filepath.Join(toolsDir, settings.mediaSubdir(), displayName+".png")
+		useGlobalBg, forceBlack := settings.artworkBgParams()
+		candidates := artSearchCandidates(artworkSrc, "", displayName, settings)
+		if _, err := generateArtworkBg(candidates, folderPath, useGlobalBg, forceBlack, settings); err != nil {
+			log.Printf("createToolShortcut: warning: artwork: %v", err)
+			artErr = err
+		}
+	}
+
+	log.Printf("createToolShortcut: created folder=%s", folderPath)
+	return artErr, nil
+}
+
+// readPakMeta reads pak.json from a tool pak directory.
+func readPakMeta(pakPath string) (PakMeta, error) {
+	var meta PakMeta
+	data, err := os.ReadFile(filepath.Join(pakPath, "pak.json"))
+	if err != nil {
+		return meta, err
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("parsing pak.json: %w", err)
+	}
+	return meta, nil
+}
+
+// checkToolDependencies returns the paks listed under "requires" in the tool's pak.json
+// that are not installed in the Tools directory. Entries without a .pak suffix get one.
+// Returns nil when there is no pak.json or nothing is missing.
+func checkToolDependencies(pak ToolPak) []string {
+	meta, err := readPakMeta(pak.Path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("checkToolDependencies: %s: %v", pak.Path, err)
+		}
+		return nil
+	}
+	_, toolsDir, _ := getBasePaths()
+	var missing []string
+	for _, req := range meta.Requires {
+		req = strings.TrimSpace(req)
+		if req == "" {
+			continue
+		}
+		if !strings.HasSuffix(req, ".pak") {
+			req += ".pak"
+		}
+		if _, err := os.Stat(filepath.Join(toolsDir, req)); err != nil {
+			missing = append(missing, req)
+		}
+	}
+	return missing
+}
+
+// writeToolEnv stores env as env.json and generates the env.sh export snippet sourced by
+// the bridge emu. Names that are not valid shell identifiers are rejected.
+func writeToolEnv(folderPath string, env map[string]string) error {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		if !isValidEnvName(k) {
+			return fmt.Errorf("invalid environment variable name %q", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling env: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(folderPath, toolEnvFile), data, 0644); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Generated by Shortcuts from env.json - edits here are overwritten.\n")
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "export %s=%s\n", k, shellQuote(env[k]))
+	}
+	return os.WriteFile(filepath.Join(folderPath, toolEnvScript), []byte(sb.String()), 0644)
+}
+
+// readToolEnv reads env.json from a tool shortcut folder.
+// Returns nil if the file does not exist or cannot be parsed.
+func readToolEnv(folderPath string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(folderPath, toolEnvFile))
+	if err != nil {
+		return nil
+	}
+	var env map[string]string
+	if err := json.Unmarshal(data, &env); err != nil {
+		log.Printf("readToolEnv: parse %s: %v", folderPath, err)
+		return nil
+	}
+	return env
+}
+
+// removeShortcut moves a shortcut folder into Roms/.trash/ (trashDirName), so adding a
+// shortcut with the same name and tag later can restore it (see findTrashedShortcut). An
+// older trashed folder with the same name is replaced.
+func removeShortcut(shortcutPath string) error {
+	trashDir := filepath.Join(filepath.Dir(shortcutPath), trashDirName)
+	dest := filepath.Join(trashDir, filepath.Base(shortcutPath))
+	log.Printf("removeShortcut: path=%s trash=%s", shortcutPath, dest)
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return fmt.Errorf("creating trash dir: %w", err)
+	}
+	if err := os.RemoveAll(dest); err != nil {
+		return fmt.Errorf("replacing trashed shortcut: %w", err)
+	}
+	if err := os.Rename(shortcutPath, dest); err != nil {
+		return fmt.Errorf("moving shortcut to trash: %w", err)
+	}
+	return nil
+}
+
+// ── Shortcut registry ────────────────────────────────────────
+
+// registryVersion is bumped whenever the registry.json layout changes incompatibly.
+const registryVersion = 1
+
+// ShortcutRegistry is the document written to registry.json so other tools can discover
+// shortcuts without re-implementing the scan. Layout (version 1):
+//
+//	{
+//	  "version": 1,
+//	  "updated_at": "2024-03-15T14:22:05Z",
+//	  "shortcuts": [
+//	    {"display_name": "Battletoads", "folder": "\uFEFFBattletoads (MD)", "path": "/mnt/SDCARD/Roms/...",
+//	     "tag": "MD", "type": "rom", "target": "/mnt/SDCARD/Roms/Sega Genesis (MD)/Battletoads.md",
+//	     "position": "bottom"}
+//	  ]
+//	}
+//
+// "type" is "rom" or "tool"; "position" is "top", "bottom" or "alpha". Entries are sorted by
+// display name, matching scanShortcuts.
+type ShortcutRegistry struct {
+	Version   int             `json:"version"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Shortcuts []RegistryEntry `json:"shortcuts"`
+}
+
+// RegistryEntry describes a single shortcut in registry.json.
+type RegistryEntry struct {
+	DisplayName string `json:"display_name"`
+	Folder      string `json:"folder"`
+	Path        string `json:"path"`
+	Tag         string `json:"tag"`
+	Type        string `json:"type"`
+	Target      string `json:"target"`
+	Position    string `json:"position"`
+}
+
+// getRegistryPath returns the path to the shared shortcut registry JSON file.
+func getRegistryPath() string {
+	return filepath.Join(getAppDataDir(), "registry.json")
+}
+
+// updateShortcutRegistry rescans all shortcuts and rewrites registry.json.
+// Called once at the end of every operation that creates, deletes or renames shortcuts;
+// the per-shortcut helpers (createROMShortcut, removeShortcut, ...) leave it to their
+// callers so bulk operations rescan only once.
+func updateShortcutRegistry(settings AppSettings) error {
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		return fmt.Errorf("scanning shortcuts: %w", err)
+	}
+	reg := ShortcutRegistry{
+		Version:   registryVersion,
+		UpdatedAt: time.Now().UTC(),
+		Shortcuts: make([]RegistryEntry, 0, len(shortcuts)),
+	}
+	for _, sc := range shortcuts {
+		kind := "rom"
+		switch {
+		case sc.IsTool:
+			kind = "tool"
+		case sc.IsSaveState:
+			kind = "savestate"
+		}
+		reg.Shortcuts = append(reg.Shortcuts, RegistryEntry{
+			DisplayName: sc.Display,
+			Folder:      sc.Name,
+			Path:        sc.Path,
+			Tag:         sc.Tag,
+			Type:        kind,
+			Target:      sc.TargetPath,
+			Position:    sc.Position.String(),
+		})
+	}
+
+	path := getRegistryPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating registry dir: %w", err)
+	}
+	data, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling registry: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	log.Printf("updateShortcutRegistry: wrote %d shortcuts to %s", len(reg.Shortcuts), path)
+	return nil
+}
+
+// ── Bridge emu management ────────────────────────────────────
+
+// bridgeScriptVersion is written into launch.sh as "# version: N". Bump it whenever the
+// bridge body changes so the installed copy is recognisably outdated.
+const bridgeScriptVersion = 4
+
+// bridgeShebang returns the interpreter line of the bridge script for platform p. TG3040,
+// TG5040 and TG5050 all run NextUI's busybox sh, so they share one.
+func bridgeShebang(p Platform) string {
+	return "#!/bin/sh"
+}
+
+// bridgeLaunchBody is the platform-independent part of a bridge pak's launch.sh, with %[1]s
+// standing for the script it runs in the tool pak: launch.sh for SHORTCUT.pak,
+// directLaunchScript for SHORTCUT_DIRECT.pak. $1 is the path to the shortcut's "target"
+// file, which holds the real tool .pak path. Save state shortcuts target an emulator pak,
+// which is given the ROM (relative entries resolved against the shortcut folder) and the
+// state file as arguments.
+const bridgeLaunchBody = `TARGET=$(cat "$1")
+SHORTCUT_DIR=$(dirname "$1")
+if [ -f "$SHORTCUT_DIR/env.sh" ]; then
+    . "$SHORTCUT_DIR/env.sh"
+fi
+set --
+if [ -f "$SHORTCUT_DIR/` + shortcutROMFile + `" ]; then
+    ROM=$(cat "$SHORTCUT_DIR/` + shortcutROMFile + `")
+    case "$ROM" in
+    /*) ;;
+    *) ROM="$SHORTCUT_DIR/$ROM" ;;
+    esac
+    set -- "$ROM"
+fi
+if [ -f "$SHORTCUT_DIR/` + shortcutSaveStateFile + `" ]; then
+    set -- "$@" "$SHORTCUT_DIR/` + shortcutSaveStateFile + `"
+fi
+if [ -x "$TARGET/%[1]s" ]; then
+    exec "$TARGET/%[1]s" "$@"
+fi
+`
+
+// toolLaunchPreview returns what the bridge will run for a tool shortcut to pakPath with
+// env: an export line per variable (as env.sh would set them), then the exec line of
+// pakPath's launch.sh, or its directLaunchScript when it has one (see toolShortcutTag).
+// runnable is false when that script is missing or not executable, in which case the
+// bridge exits without launching anything.
+func toolLaunchPreview(pakPath string, env map[string]string) (preview string, runnable bool) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "export %s=%s\n", k, shellQuote(env[k]))
+	}
+	script := "launch.sh"
+	if toolShortcutTag(pakPath) == directBridgeEmuTag {
+		script = directLaunchScript
+	}
+	launch := filepath.Join(pakPath, script)
+	fmt.Fprintf(&sb, "exec \"%s\"", launch)
+	info, err := os.Stat(launch)
+	runnable = err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+	return sb.String(), runnable
+}
+
+// bridgeLaunchScriptForPlatform returns the SHORTCUT.pak launch.sh tuned for platform p.
+// resources/SHORTCUT.pak/launch.sh, shipped in the .pakz, is a copy of its output for the
+// TrimUI platforms; TestBridgeResourceMatchesScript keeps the two in step.
+func bridgeLaunchScriptForPlatform(p Platform) string {
+	return bridgeScript(p, "SHORTCUT.pak - Bridge emulator for tool and save state shortcuts.", "launch.sh")
+}
+
+// directBridgeLaunchScriptForPlatform returns the SHORTCUT_DIRECT.pak launch.sh for
+// platform p, which runs the tool's directLaunchScript instead of its launch.sh.
+func directBridgeLaunchScriptForPlatform(p Platform) string {
+	return bridgeScript(p, "SHORTCUT_DIRECT.pak - Bridge emulator for paks with their own shortcut launcher.", directLaunchScript)
+}
+
+// bridgeScript assembles a bridge launch.sh: shebang, description and version comments,
+// then bridgeLaunchBody running target in the tool pak.
+func bridgeScript(p Platform, description, target string) string {
+	return fmt.Sprintf("%s\n# %s\n# version: %d\n%s",
+		bridgeShebang(p), description, bridgeScriptVersion, fmt.Sprintf(bridgeLaunchBody, target))
+}
+
+// ensureBridgeEmu makes sure SHORTCUT.pak exists for tool shortcuts.
+// An existing launch.sh is rewritten when it differs from bridgeLaunchScriptForPlatform so
+// that older installs pick up new bridge features (e.g. env.sh passthrough).
+// Returns an error naming the directory when it can't be written (e.g. a read-only mount).
+func ensureBridgeEmu() error {
+	return installBridgePak(bridgeEmuTag, bridgeLaunchScriptForPlatform(platform))
+}
+
+// ensureDirectBridgeEmu makes sure SHORTCUT_DIRECT.pak exists for tool shortcuts to paks
+// with a directLaunchScript. It is installed when the first such shortcut is created, and
+// kept up to date like SHORTCUT.pak afterwards.
+func ensureDirectBridgeEmu() error {
+	return installBridgePak(directBridgeEmuTag, directBridgeLaunchScriptForPlatform(platform))
+}
+
+// directBridgeInstalled reports whether SHORTCUT_DIRECT.pak has been installed before.
+func directBridgeInstalled() bool {
+	_, ok := findBridgePakPath(directBridgeEmuTag)
+	return ok
+}
+
+// bridgePakDirs returns where the bridge pak for tag may live, in order of preference:
+// Emus/<platform>/ on the card, then the built-in emulator folders (systemEmuSubdirs) for
+// cards whose user Emus folder is missing or read-only. A built-in folder is only offered
+// when it already exists, matching emuDirsExist, so none is ever created here.
+func bridgePakDirs(tag string) []string {
+	romsDir, _, emusDir := getBasePaths()
+	pakName := tag + ".pak"
+	dirs := []string{filepath.Join(emusDir, pakName)}
+	systemDir := filepath.Join(filepath.Dir(romsDir), ".system", platform.dirName())
+	for _, sub := range systemEmuSubdirs {
+		if dirExists(filepath.Join(systemDir, sub)) {
+			dirs = append(dirs, filepath.Join(systemDir, sub, pakName))
+		}
+	}
+	return dirs
+}
+
+// findBridgePakPath returns the first of bridgePakDirs(tag) holding a non-empty launch.sh.
+func findBridgePakPath(tag string) (string, bool) {
+	for _, dir := range bridgePakDirs(tag) {
+		info, err := os.Stat(filepath.Join(dir, "launch.sh"))
+		if err == nil && info.Mode().IsRegular() && info.Size() > 0 {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// findBridgeEmuPath returns the installed SHORTCUT.pak folder, user Emus first.
+func findBridgeEmuPath() (string, bool) {
+	return findBridgePakPath(bridgeEmuTag)
+}
+
+// bridgeEmuExists reports whether SHORTCUT.pak is installed in either location.
+func bridgeEmuExists() bool {
+	_, ok := findBridgeEmuPath()
+	return ok
+}
+
+// installBridgePak writes script as <tag>.pak/launch.sh unless it is already there
+// unchanged. An existing install is updated where it is; otherwise the pak goes into
+// Emus/<platform>/, or into an existing .system/<platform>/paks/Emus/ when that isn't
+// writable.
+// See ensureBridgeEmu.
+func installBridgePak(tag, script string) error {
+	if platform == PlatformMac {
+		return nil // not needed on macOS
+	}
+
+	pakName := tag + ".pak"
+	_, _, emusDir := getBasePaths()
+	dirs := bridgePakDirs(tag)
+	if found, ok := findBridgePakPath(tag); ok {
+		dirs = []string{found}
+		launchPath := filepath.Join(found, "launch.sh")
+		if data, err := os.ReadFile(launchPath); err == nil && string(data) == script {
+			log.Printf("installBridgePak: already present at %s", launchPath)
+			return nil // already up to date
+		}
+	} else if !emuDirsExist(emusDir) {
+		log.Printf("installBridgePak: warning: neither %s nor a built-in emulator folder exists; not creating it", emusDir)
+		return fmt.Errorf("installing %s: %w", pakName, errNoEmusDir)
+	}
+
+	var firstErr error
+	for _, pakDir := range dirs {
+		err := writeBridgePak(pakDir, script)
+		if err == nil {
+			log.Printf("installBridgePak: wrote %s version %d at %s", pakName, bridgeScriptVersion, pakDir)
+			return nil
+		}
+		log.Printf("installBridgePak: %s: %v", pakDir, err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return fmt.Errorf("installing %s: %w", pakName, firstErr)
+}
+
+// writeBridgePak creates pakDir if needed and writes script as its launch.sh.
+func writeBridgePak(pakDir, script string) error {
+	if err := checkDirWritable(pakDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(pakDir, 0755); err != nil {
+		return fmt.Errorf("creating dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(pakDir, "launch.sh"), []byte(script), 0755); err != nil {
+		return fmt.Errorf("writing launch.sh: %w", err)
+	}
+	return nil
+}
+
+// errNoEmusDir is returned by ensureBridgeEmu when the card has no emulator folders at all,
+// e.g. an unexpected NextUI build or a card that isn't set up yet.
+var errNoEmusDir = errors.New("no emulator folder found")
+
+// emuDirsExist reports whether emusDir or one of the built-in emulator folders
+// (systemEmuSubdirs) exists. ensureBridgeEmu only creates Emus/<platform>/ when one does, so
+// it doesn't add a folder layout NextUI might not expect.
+func emuDirsExist(emusDir string) bool {
+	if dirExists(emusDir) {
+		return true
+	}
+	romsDir, _, _ := getBasePaths()
+	systemDir := filepath.Join(filepath.Dir(romsDir), ".system", platform.dirName())
+	for _, sub := range systemEmuSubdirs {
+		if dirExists(filepath.Join(systemDir, sub)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDirWritable verifies that files can be created in path by creating and removing a
+// temporary file. If path doesn't exist yet, its nearest existing parent is checked, since
+// that is where MkdirAll will write.
+func checkDirWritable(path string) error {
+	dir := path
+	for {
+		if info, err := os.Stat(dir); err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return fmt.Errorf("%s: no existing parent directory", path)
+		}
+		dir = parent
+	}
+	f, err := os.CreateTemp(dir, ".writetest-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// verifyBridgeEmu reports whether SHORTCUT.pak/launch.sh exists and is non-empty, in
+// either bridgePakDirs location.
+func verifyBridgeEmu() bool {
+	if platform == PlatformMac {
+		return true // not needed on macOS
+	}
+	return bridgeEmuExists()
+}
+
+// ── String utilities ─────────────────────────────────────────
+
+// isHidden checks if a name should be hidden (dotfiles, .disabled, map.txt).
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".") ||
+		strings.HasSuffix(name, ".disabled") ||
+		name == "map.txt"
+}
+
+// isValidEnvName reports whether name is a valid POSIX shell variable name.
+func isValidEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// shellQuote wraps s in single quotes for safe use in a POSIX shell script.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isMacDotfile returns true for dot-prefixed names that are Mac/system artifacts
+// rather than user-created hidden content. Used when ShowHidden is on to avoid
+// surfacing .DS_Store, .Spotlight-V100, etc. while still showing user-hidden folders.
+// AppleDouble resource forks ("._Mario.smc", "._Roms", ".__MACOSX") that macOS writes
+// next to every file on FAT32 are always artifacts, even when they carry a (TAG).
+func isMacDotfile(name string) bool {
+	if !strings.HasPrefix(name, ".") {
+		return false
+	}
+	if strings.HasPrefix(name, "._") {
+		return true
+	}
+	// Any dot-dir that also has a (TAG) suffix is likely a user-created hidden console —
+	// show it. Everything else (no tag) is treated as system/Mac cruft.
+	return extractTag(name) == ""
+}
+
+// fileExists reports whether path exists and is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// dirExists reports whether path exists and is a directory.
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// isDirEmpty reports whether dir has no entries at all. Unreadable dirs count as empty.
+func isDirEmpty(path string) bool {
+	entries, err := os.ReadDir(path)
+	return err != nil || len(entries) == 0
+}
+
+// dirHasVisibleContent reports whether dir contains at least one entry that is not hidden.
+// Used with isDirEmpty to tell dot-file-only console folders apart from empty ones.
+func dirHasVisibleContent(path string) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !isHidden(e.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isShortcutFolder checks if a full folder path is a shortcut.
+// Detects: folders with a Bottom prefix (current, old or legacy ★), and folders
+// with a .shortcut marker file (Top/Alpha positions).
+func isShortcutFolder(folderPath string, prefixes sortPrefixes) bool {
+	if pos, _ := prefixes.match(filepath.Base(folderPath)); pos == ShortcutPositionBottom {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(folderPath, shortcutMarkerFile))
+	return err == nil
+}
+
+// knownRegions lists No-Intro style region and video-standard codes, which extractTag
+// skips over because they are never emulator tags.
+var knownRegions = map[string]bool{
+	"USA": true, "EUR": true, "JPN": true, "Europe": true, "Japan": true, "World": true,
+	"PAL": true, "NTSC": true, "NTSC-U": true, "NTSC-J": true, "Asia": true, "Korea": true,
+}
+
+// extractTag extracts the emulator tag from a directory name.
+// e.g. "Game Boy Advance (GBA)" -> "GBA", "\u200BBattletoads (MD)" -> "MD"
+// Region codes are skipped: "Super Mario World (SNES) (USA)" -> "SNES". If every group is
+// a region code the last one is returned, as before.
+func extractTag(name string) string {
+	_, tag := splitTag(name)
+	return tag
+}
+
+// extractDisplayName extracts the display name, stripping the (TAG) group extractTag picks.
+// e.g. "\u200BBattletoads (MD)" -> "\u200BBattletoads",
+// "Super Mario World (SNES) (USA)" -> "Super Mario World (USA)"
+func extractDisplayName(name string) string {
+	display, _ := splitTag(name)
+	return display
+}
+
+// splitTag parses name for extractTag and extractDisplayName, so both agree on which
+// "(...)" group is the tag. Returns name unchanged and no tag if it has no group.
+func splitTag(name string) (display, tag string) {
+	start, end := -1, -1
+	for rest := name; ; {
+		idx := strings.LastIndex(rest, "(")
+		if idx < 0 {
+			break
+		}
+		rp := strings.LastIndex(rest, ")")
+		if rp <= idx {
+			break
+		}
+		group := strings.TrimSpace(rest[idx+1 : rp])
+		if start < 0 || !knownRegions[group] {
+			start, end, tag = idx, rp, group
+		}
+		if !knownRegions[group] {
+			break
+		}
+		rest = rest[:idx]
+	}
+	if start < 0 {
+		return name, ""
+	}
+	display = strings.TrimRight(name[:start], " ") + name[end+1:]
+	return strings.TrimSpace(display), tag
+}
+
+// folderDisplayName returns the display name a shortcut folder's name implies: the name
+// without its sort prefix and trailing (TAG).
+func folderDisplayName(name, prefix string) string {
+	return strings.TrimPrefix(extractDisplayName(name), prefix)
+}
+
+// folderNameMatches reports whether the folder name still carries display, allowing the
+// " (N)" that resolveNameConflict adds to duplicates.
+func folderNameMatches(name, prefix, display string) bool {
+	folder := folderDisplayName(name, prefix)
+	display = sanitizeForFAT32(display)
+	if folder == display {
+		return true
+	}
+	n, ok := strings.CutPrefix(folder, display+" (")
+	if !ok {
+		return false
+	}
+	n, ok = strings.CutSuffix(n, ")")
+	_, err := strconv.Atoi(n)
+	return ok && err == nil
+}
+
+// fat32Replacer swaps the characters FAT32 forbids in file names for look-alikes that are
+// allowed. Brackets become angle quotes rather than parentheses, which would be mistaken
+// for the (TAG).
+var fat32Replacer = strings.NewReplacer(
+	`"`, "'",
+	":", "-",
+	"/", "\u2044", // fraction slash
+	`\`, "\u2044",
+	"|", "-",
+	"*", "-",
+	"<", "\u2039", // single left-pointing angle quotation mark
+	">", "\u203A", // single right-pointing angle quotation mark
+	"?", "",
+)
+
+// sanitizeForFAT32 returns name with the characters FAT32 doesn't allow replaced (see
+// fat32Replacer) and control characters removed, e.g. "Ys: Ancient/Book I?" becomes
+// "Ys- Ancient⁄Book I".
+func sanitizeForFAT32(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	return fat32Replacer.Replace(name)
+}
+
+// stripExtension removes the file extension from a filename. The extension, dot
+// included, must be 2–5 characters; they're counted as runes so a non-Latin extension
+// isn't judged by its UTF-8 byte length.
+func stripExtension(name string) string {
+	ext := filepath.Ext(name)
+	if n := utf8.RuneCountInString(ext); n >= 2 && n <= 5 {
+		return strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// buildFolderName constructs the shortcut folder name for the given position, using the
+// current prefixes. With the defaults:
+//
+//	Bottom: "\uFEFFBattletoads (World) (MD)"  (invisible prefix, sorts after Z)
+//	Top:    "0) Battletoads (World) (MD)"
+//	Alpha:  "Battletoads (World) (MD)"
+//
+// Characters FAT32 doesn't allow in file names are replaced first (see sanitizeForFAT32).
+func buildFolderName(pos ShortcutPosition, displayName, tag string, prefixes sortPrefixes) string {
+	if safe := sanitizeForFAT32(displayName); safe != displayName {
+		log.Printf("buildFolderName: sanitized %q -> %q", displayName, safe)
+		displayName = safe
+	}
+	base := fmt.Sprintf("%s (%s)", displayName, tag)
+	switch pos {
+	case ShortcutPositionTop:
+		return prefixes.Top + base
+	case ShortcutPositionAlpha:
+		return base
+	default: // ShortcutPositionBottom — the invisible prefix needs no space separator
+		return prefixes.Bottom + base
+	}
+}
+
+// maxNameConflicts bounds the numbered variants resolveNameConflict tries.
+const maxNameConflicts = 100
+
+// resolveNameConflict returns folderName, or a numbered variant if a folder of that name
+// already exists in romsDir, so creating a shortcut never overwrites another folder's m3u.
+// The number goes before the trailing (TAG) so the tag stays last and is still detected:
+//
+//	"Tetris (GB)" -> "Tetris (2) (GB)" -> "Tetris (3) (GB)" …
+//
+// Returns an error if a name can't be checked or all maxNameConflicts variants are taken.
+func resolveNameConflict(romsDir, folderName string) (string, error) {
+	free := func(name string) (bool, error) {
+		_, err := os.Stat(filepath.Join(romsDir, name))
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("checking %s: %w", name, err)
+		}
+		return false, nil
+	}
+	if ok, err := free(folderName); ok || err != nil {
+		return folderName, err
+	}
+	base, tagPart := folderName, ""
+	if idx := strings.LastIndex(folderName, " ("); idx >= 0 && strings.HasSuffix(folderName, ")") {
+		base, tagPart = folderName[:idx], folderName[idx:]
+	}
+	for n := 2; n <= maxNameConflicts; n++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, n, tagPart)
+		ok, err := free(candidate)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			log.Printf("resolveNameConflict: %q exists, using %q", folderName, candidate)
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%d folders named like %q already exist", maxNameConflicts, folderName)
+}
+
+// generateArtworkBg composites a fullscreen bg.png for a shortcut's .media/ folder by
+// running the artwork pipeline (see buildArtPipeline). With the default pipeline, the
+// device's global /mnt/SDCARD/bg.png is the base layer when useGlobalBg is true (otherwise
+// the canvas is plain black), and the art is overlaid right-aligned at the NextUI
+// SCREEN_GAMELIST thumbnail dimensions (screen_w*0.45 × screen_h*0.60).
+// The art is the first of artSrcPaths that exists (see resolveArtSrcPaths).
+// When forceBlack is true a bg.png is written even when none exists (base layer only, no
+// art overlay). When forceBlack is false and no source art exists, nothing is written.
+// settings controls optional art processing (e.g. ArtAutoCrop).
+// The returned ArtStats describe the run; SkippedReason is set when nothing was written
+// without an error.
+func generateArtworkBg(artSrcPaths []string, destFolder string, useGlobalBg, forceBlack bool, settings AppSettings) (stats ArtStats, err error) {
+	start := time.Now()
+	defer func() {
+		stats.Duration = time.Since(start)
+		recordArtRun(stats, err)
+	}()
+
+	artSrcPath := firstExistingPath(artSrcPaths)
+
+	canvas, sourceBytes, err := composeArtworkBg(artSrcPath, readShortcutMarker(destFolder, settings.sortPrefixes()), useGlobalBg, forceBlack, settings)
+	stats.SourceBytes = sourceBytes
+	if err != nil {
+		return stats, err
+	}
+	if canvas == nil {
+		stats.SkippedReason = "no source art"
+		return stats, nil // no art and not forcing — skip silently
+	}
+	screenW, screenH := canvas.Bounds().Dx(), canvas.Bounds().Dy()
+
+	// Save composite.
+	mediaDir := filepath.Join(destFolder, settings.mediaSubdir())
+	if err := os.MkdirAll(mediaDir, 0755); err != nil {
+		return stats, fmt.Errorf("creating %s folder: %w", settings.mediaSubdir(), err)
+	}
+	bgPath := filepath.Join(mediaDir, "bg.png")
+	info, err := writeArtPNG(bgPath, canvas)
+	if err != nil {
+		return stats, err
+	}
+	stats.OutputBytes = info.Size()
+	recordGeneratedArt(destFolder, info.ModTime())
+	log.Printf("generateArtworkBg: %s (%dx%d)", bgPath, screenW, screenH)
+
+	if settings.MultiPageArt {
+		stats.OutputBytes += generateArtPages(destFolder, useGlobalBg, settings)
+	} else {
+		removeArtPages(mediaDir, 1)
+	}
+	return stats, nil
+}
+
+// artEncodeRetries is how many more times writeArtPNG tries after a failed write, and
+// artEncodeRetryDelay the pause before each retry. Slow SD cards occasionally fail a write
+// with a transient I/O error that succeeds moments later.
+const (
+	artEncodeRetries    = 3
+	artEncodeRetryDelay = 200 * time.Millisecond
+)
+
+// writeArtPNG encodes canvas to path and returns the written file's info, retrying up to
+// artEncodeRetries times. The last error is returned when every attempt fails.
+func writeArtPNG(path string, canvas image.Image) (os.FileInfo, error) {
+	var err error
+	for attempt := 0; attempt <= artEncodeRetries; attempt++ {
+		if attempt > 0 {
+			log.Printf("writeArtPNG: %s: %v — retry %d/%d", path, err, attempt, artEncodeRetries)
+			time.Sleep(artEncodeRetryDelay)
+		}
+		var info os.FileInfo
+		if info, err = encodeArtPNG(path, canvas); err == nil {
+			return info, nil
+		}
+	}
+	return nil, err
+}
+
+// encodeArtPNG is one writeArtPNG attempt. The PNG is encoded into path+".tmp" and only
+// then moved over path, so a power loss mid-encode leaves the previous file intact; a
+// retry starts the temp file afresh.
+func encodeArtPNG(path string, canvas image.Image) (os.FileInfo, error) {
+	tmpPath := path + ".tmp"
+	if err := encodePNGFile(tmpPath, canvas); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := replaceFile(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	return os.Stat(path)
+}
+
+// pngEncode is the encoder used by encodePNGFile; tests swap it to cut a write short.
+var pngEncode = png.Encode
+
+// encodePNGFile writes canvas to path as a PNG and syncs it to disk.
+func encodePNGFile(path string, canvas image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Base(path), err)
+	}
+	defer f.Close()
+	if err := pngEncode(f, canvas); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("sync %s: %w", filepath.Base(path), err)
+	}
+	return f.Close()
+}
+
+// replaceFile moves src over dst. When the rename fails (e.g. src and dst are on
+// different filesystems) it copies src to dst and removes src instead.
+func replaceFile(src, dst string) error {
+	renameErr := os.Rename(src, dst)
+	if renameErr == nil {
+		return nil
+	}
+	log.Printf("replaceFile: rename %s: %v — copying instead", filepath.Base(src), renameErr)
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", filepath.Base(src), err)
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Base(dst), err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("copy to %s: %w", filepath.Base(dst), err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", filepath.Base(dst), err)
+	}
+	in.Close()
+	return os.Remove(src)
+}
+
+// artPageFile returns the .media file name of artwork page n (1-based): bg.png, bg2.png,
+// bg3.png, ...
+func artPageFile(n int) string {
+	if n <= 1 {
+		return "bg.png"
+	}
+	return fmt.Sprintf("bg%d.png", n)
+}
+
+// artPageNumber returns n for an extra artwork page file named bg<n>.png (n >= 2), or 0.
+func artPageNumber(name string) int {
+	num, ok := strings.CutPrefix(name, "bg")
+	if !ok {
+		return 0
+	}
+	num, ok = strings.CutSuffix(num, ".png")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 2 {
+		return 0
+	}
+	return n
+}
+
+// generateArtPages writes bg2.png, bg3.png, ... in destFolder/.media for the
+// AdditionalArtPaths listed in its marker, for themes that cycle through several
+// backgrounds. Pages are numbered without gaps: a source that is missing or fails to
+// render is logged and skipped. Pages left over from a longer list are removed. Returns
+// the total size written.
+func generateArtPages(destFolder string, useGlobalBg bool, settings AppSettings) (outputBytes int64) {
+	meta, _ := readShortcutMeta(destFolder)
+	mediaDir := filepath.Join(destFolder, settings.mediaSubdir())
+	page := 1
+	for _, src := range meta.AdditionalArtPaths {
+		canvas, _, err := composeArtworkBg(src, meta.DisplayName, useGlobalBg, false, settings)
+		if err != nil {
+			log.Printf("generateArtPages: %s: %v", src, err)
+			continue
+		}
+		if canvas == nil {
+			log.Printf("generateArtPages: %s: source missing", src)
+			continue
+		}
+		info, err := writeArtPNG(filepath.Join(mediaDir, artPageFile(page+1)), canvas)
+		if err != nil {
+			log.Printf("generateArtPages: %s: %v", src, err)
+			continue
+		}
+		page++
+		outputBytes += info.Size()
+	}
+	removeArtPages(mediaDir, page)
+	if page > 1 {
+		log.Printf("generateArtPages: %s: %d extra page(s)", destFolder, page-1)
+	}
+	return outputBytes
+}
+
+// removeArtPages deletes the extra artwork pages in mediaDir numbered above keep.
+func removeArtPages(mediaDir string, keep int) {
+	entries, err := os.ReadDir(mediaDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if n := artPageNumber(e.Name()); n > keep {
+			path := filepath.Join(mediaDir, e.Name())
+			if err := os.Remove(path); err != nil {
+				log.Printf("removeArtPages: remove %s: %v", path, err)
+			}
+		}
+	}
+}
+
+// listArtImages returns the images in dir that can be used as artwork sources, sorted by
+// name. Subdirectories and macOS dotfiles are skipped.
+func listArtImages(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() || isMacDotfile(e.Name()) {
+			continue
+		}
+		if _, ok := imageFormatExts[strings.ToLower(filepath.Ext(e.Name()))]; ok {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// generateArtworkBgDryRun runs the same pipeline as generateArtworkBg and returns the
+// canvas instead of writing it, for previewing artwork settings. Nothing on disk is
+// touched. There is no shortcut folder, so the caption stage has no display name to draw.
+// When artSrcPath is missing and forceBlack is false it returns (nil, nil), matching the
+// case where generateArtworkBg writes nothing.
+func generateArtworkBgDryRun(artSrcPath string, useGlobalBg, forceBlack bool, settings AppSettings) (image.Image, error) {
+	canvas, _, err := composeArtworkBg(artSrcPath, "", useGlobalBg, forceBlack, settings)
+	if err != nil || canvas == nil {
+		return nil, err
+	}
+	return canvas, nil
+}
+
+// composeArtworkBg loads the source art and runs the artwork pipeline over it. canvas is
+// nil when artSrcPath is missing and forceBlack is false. sourceBytes is the size of the
+// source file, 0 if none.
+func composeArtworkBg(artSrcPath, displayName string, useGlobalBg, forceBlack bool, settings AppSettings) (canvas *image.NRGBA, sourceBytes int64, err error) {
+	var artImg image.Image
+	if info, err := os.Stat(artSrcPath); err == nil {
+		sourceBytes = info.Size()
+		img, err := loadImage(artSrcPath)
+		if err != nil {
+			return nil, sourceBytes, fmt.Errorf("load art: %w", err)
+		}
+		artImg = img
+		if settings.ArtAutoCrop {
+			artImg = autoCropImage(artImg, artAutoCropThreshold)
+		}
+	} else if !forceBlack {
+		return nil, 0, nil
+	}
+
+	ctx := &ArtContext{
+		Art:         artImg,
+		DisplayName: displayName,
+		UseGlobalBg: useGlobalBg,
+		Settings:    settings,
+	}
+	return runArtPipeline(buildArtPipeline(settings), ctx), sourceBytes, nil
+}
+
+// ArtStats describes one generateArtworkBg run.
+type ArtStats struct {
+	Duration      time.Duration
+	OutputBytes   int64  // size of the written bg.png
+	SourceBytes   int64  // size of the source artwork file, 0 if none
+	SkippedReason string // why no bg.png was written, "" if one was
+}
+
+// ArtTotals accumulates ArtStats over a bulk artwork operation, and over every run since
+// the app was installed in AppSettings.ArtTotals (see flushArtTotals).
+type ArtTotals struct {
+	Generated   int           `json:"generated"`
+	Skipped     int           `json:"skipped"`
+	Failed      int           `json:"failed"`
+	Duration    time.Duration `json:"duration_ns"`
+	OutputBytes int64         `json:"output_bytes"`
+	SourceBytes int64         `json:"source_bytes"`
+}
+
+// merge adds the counts and sizes of o to t.
+func (t *ArtTotals) merge(o ArtTotals) {
+	t.Generated += o.Generated
+	t.Skipped += o.Skipped
+	t.Failed += o.Failed
+	t.Duration += o.Duration
+	t.OutputBytes += o.OutputBytes
+	t.SourceBytes += o.SourceBytes
+}
+
+// artRuns collects every generateArtworkBg run (recordArtRun) until flushArtTotals adds
+// them to the totals in settings.json.
+var artRuns struct {
+	sync.Mutex
+	pending ArtTotals
+}
+
+// recordArtRun adds one generateArtworkBg run to artRuns.
+func recordArtRun(stats ArtStats, err error) {
+	artRuns.Lock()
+	artRuns.pending.add(stats, err)
+	artRuns.Unlock()
+}
+
+// flushArtTotals adds the runs recorded since the last flush to AppSettings.ArtTotals and
+// saves the settings. The runs are kept for the next flush if saving fails.
+func flushArtTotals() error {
+	artRuns.Lock()
+	defer artRuns.Unlock()
+	if artRuns.pending == (ArtTotals{}) {
+		return nil
+	}
+	s := loadSettings()
+	s.ArtTotals.merge(artRuns.pending)
+	if err := saveSettings(s); err != nil {
+		return err
+	}
+	log.Printf("flushArtTotals: added generated=%d skipped=%d failed=%d; total generated=%d",
+		artRuns.pending.Generated, artRuns.pending.Skipped, artRuns.pending.Failed, s.ArtTotals.Generated)
+	artRuns.pending = ArtTotals{}
+	return nil
+}
+
+// add folds one run into the totals; err is the error returned by generateArtworkBg.
+func (t *ArtTotals) add(stats ArtStats, err error) {
+	t.Duration += stats.Duration
+	t.SourceBytes += stats.SourceBytes
+	switch {
+	case err != nil:
+		t.Failed++
+	case stats.SkippedReason != "":
+		t.Skipped++
+	default:
+		t.Generated++
+		t.OutputBytes += stats.OutputBytes
+	}
+}
+
+// Caption positions for the artwork text overlay.
+const (
+	ArtTextBottomLeft   = "bottom-left"
+	ArtTextBottomCenter = "bottom-center"
+	ArtTextTopLeft      = "top-left"
+)
+
+// artTextMargin is the gap in pixels between the caption and the screen edges.
+const artTextMargin = 40
+
+// loadCaptionFace returns the font face for the artwork caption: the TTF/OTF at fontPath
+// when set and readable, otherwise the bundled Go Bold font. Size scales with the screen.
+func loadCaptionFace(fontPath string, screenH int) (font.Face, error) {
+	data := gobold.TTF
+	if fontPath != "" {
+		if b, err := os.ReadFile(fontPath); err == nil {
+			data = b
+		} else {
+			log.Printf("loadCaptionFace: %v — using bundled font", err)
+		}
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing font: %w", err)
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    float64(screenH) / 20, // 36 px at 720p
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
+
+// drawTextOverlay renders text onto canvas in white with a soft black drop shadow.
+// position is one of the ArtText* constants (default bottom-left).
+func drawTextOverlay(canvas *image.NRGBA, text, position, fontPath string) {
+	face, err := loadCaptionFace(fontPath, canvas.Bounds().Dy())
+	if err != nil {
+		log.Printf("drawTextOverlay: %v", err)
+		return
+	}
+	defer face.Close()
+
+	const shadow = 3
+	metrics := face.Metrics()
+	textW := font.MeasureString(face, text).Ceil()
+	textH := (metrics.Ascent + metrics.Descent).Ceil()
+	screenW, screenH := canvas.Bounds().Dx(), canvas.Bounds().Dy()
+
+	x := artTextMargin
+	y := screenH - artTextMargin - textH
+	switch position {
+	case ArtTextBottomCenter:
+		x = max(artTextMargin, (screenW-textW)/2)
+	case ArtTextTopLeft:
+		y = artTextMargin
+	}
+
+	baseline := fixed.P(x, y+metrics.Ascent.Ceil())
+	d := font.Drawer{Dst: canvas, Face: face}
+	d.Src = image.NewUniform(color.NRGBA{0, 0, 0, 0xc0})
+	d.Dot = baseline.Add(fixed.P(shadow, shadow))
+	d.DrawString(text)
+	d.Src = image.White
+	d.Dot = baseline
+	d.DrawString(text)
+}
+
+// screenDimensions returns the native screen size for the current platform.
+// isBrick is set from DEVICE="brick" (exported by NextUI's launch.sh).
+//
+//	Smart Pro (DEVICE=smartpro) → 1280×720
+//	Smart Pro S (tg5050)        → 1280×720
+//	Brick       (DEVICE=brick)  → 1024×768
+//	Brick       (tg3040)        → 1024×768
+//	macOS                       → 1280×720
+//
+// Each platform has its own case so one can change resolution without affecting the others.
+func screenDimensions() (int, int) {
+	switch platform {
+	case PlatformTG3040:
+		return 1024, 768
+	case PlatformTG5050:
+		return 1280, 720
+	case PlatformTG5040:
+		if isBrick {
+			return 1024, 768
+		}
+		return 1280, 720
+	default: // PlatformMac and unknown platforms: Smart Pro layout
+		if isBrick {
+			return 1024, 768
+		}
+		return 1280, 720
+	}
+}
+
+// globalBgPath returns the path to the device's global background image on the card from
+// getSDCardRoot.
+func globalBgPath() string {
+	return filepath.Join(getSDCardRoot(), "bg.png")
+}
+
+// Image formats recognised by detectImageFormat.
+const (
+	imageFormatPNG  = "png"
+	imageFormatJPEG = "jpeg"
+	imageFormatWebP = "webp"
+)
+
+// imageFormatExts maps file extensions to the format they claim to hold.
+var imageFormatExts = map[string]string{
+	".png":  imageFormatPNG,
+	".jpg":  imageFormatJPEG,
+	".jpeg": imageFormatJPEG,
+	".webp": imageFormatWebP,
+}
+
+// detectImageFormat identifies PNG, JPEG or WebP data from its leading magic bytes, so
+// artwork is decoded correctly even when a scraper saved a JPEG under a .png name.
+// The returned reader yields the full stream, including the peeked bytes.
+func detectImageFormat(f io.Reader) (string, io.Reader, error) {
+	header := make([]byte, 12)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", nil, fmt.Errorf("reading image header: %w", err)
+	}
+	header = header[:n]
+	r := io.MultiReader(bytes.NewReader(header), f)
+
+	switch {
+	case bytes.HasPrefix(header, []byte("\x89PNG")):
+		return imageFormatPNG, r, nil
+	case bytes.HasPrefix(header, []byte("\xFF\xD8\xFF")):
+		return imageFormatJPEG, r, nil
+	case len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return imageFormatWebP, r, nil
+	}
+	return "", nil, fmt.Errorf("unrecognised image format (header % x)", header)
+}
+
+// loadImage opens and decodes a PNG, JPEG or WebP file, chosen by content rather than
+// file extension.
+func loadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	format, r, err := detectImageFormat(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if want, ok := imageFormatExts[strings.ToLower(filepath.Ext(path))]; ok && want != format {
+		log.Printf("loadImage: %s contains %s data", path, format)
+	}
+	switch format {
+	case imageFormatJPEG:
+		return jpeg.Decode(r)
+	case imageFormatWebP:
+		return webp.Decode(r)
+	default:
+		return png.Decode(r)
+	}
+}
+
+// artAutoCropThreshold is the tolerance used by autoCropImage when ArtAutoCrop is on.
+const artAutoCropThreshold = 16
+
+// autoCropImage removes uniform white or transparent borders from box art.
+// It scans inward from each edge to the first row/column containing a content pixel and
+// copies the remaining region into a new NRGBA image. threshold is the tolerance: pixels
+// with alpha <= threshold or luminance >= 255-threshold count as border. Returns img
+// unchanged when there is nothing to crop or the image is entirely border.
+func autoCropImage(img image.Image, threshold uint8) image.Image {
+	b := img.Bounds()
+	isBorder := func(x, y int) bool {
+		c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+		if c.A <= threshold {
+			return true
+		}
+		lum := (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+		return lum >= 255-int(threshold)
+	}
+	rowIsBorder := func(y, x0, x1 int) bool {
+		for x := x0; x < x1; x++ {
+			if !isBorder(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+	colIsBorder := func(x, y0, y1 int) bool {
+		for y := y0; y < y1; y++ {
+			if !isBorder(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+
+	top, bottom := b.Min.Y, b.Max.Y
+	for top < bottom && rowIsBorder(top, b.Min.X, b.Max.X) {
+		top++
+	}
+	if top == bottom {
+		return img // nothing but border
+	}
+	for bottom > top && rowIsBorder(bottom-1, b.Min.X, b.Max.X) {
+		bottom--
+	}
+	left, right := b.Min.X, b.Max.X
+	for left < right && colIsBorder(left, top, bottom) {
+		left++
+	}
+	for right > left && colIsBorder(right-1, top, bottom) {
+		right--
+	}
+
+	crop := image.Rect(left, top, right, bottom)
+	if crop == b {
+		return img
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	xdraw.Draw(out, out.Bounds(), img, crop.Min, xdraw.Src)
+	log.Printf("autoCropImage: %dx%d -> %dx%d", b.Dx(), b.Dy(), crop.Dx(), crop.Dy())
+	return out
+}
+
+// sharpenImage applies a 3×3 unsharp mask in place: each colour channel becomes
+// original + (original - blurred) * amount, clamped to [0,255], where blurred is the 3×3
+// box-blur average (edges clamped). Alpha is left untouched. amount is expected in 0.0–1.0;
+// values <= 0 are a no-op. Counteracts the softness of BiLinear upscaling small box art.
+func sharpenImage(img *image.NRGBA, amount float64) {
+	if amount <= 0 {
+		return
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	src := make([]uint8, len(img.Pix))
+	copy(src, img.Pix)
+	stride := img.Stride
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			off := y*stride + x*4
+			for c := 0; c < 3; c++ {
+				sum := 0
+				for dy := -1; dy <= 1; dy++ {
+					yy := min(max(y+dy, 0), h-1)
+					for dx := -1; dx <= 1; dx++ {
+						xx := min(max(x+dx, 0), w-1)
+						sum += int(src[yy*stride+xx*4+c])
+					}
+				}
+				orig := float64(src[off+c])
+				v := orig + (orig-float64(sum)/9)*amount
+				img.Pix[off+c] = uint8(min(max(v+0.5, 0), 255))
+			}
+		}
+	}
+}
+
+// applyRoundedCorners sets pixels outside the corner arcs to fully transparent.
+// Ports NextUI's GFX_ApplyRoundedCorners_8888: for each corner, pixels where
+// dx²+dy² > radius² (dx/dy = distance past the corner edge) are zeroed.
+// The comparison is precomputed into a (radius+1)² lookup table and only the rows and
+// columns inside the corner bands are visited, which keeps large radii cheap on the Brick.
+func applyRoundedCorners(img *image.NRGBA, radius int) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if radius <= 0 || w == 0 || h == 0 {
+		return
+	}
+
+	// edgeDist mirrors the dx/dy calculation of the C original; always in [0, radius].
+	edgeDist := func(i, n int) int {
+		if i < radius {
+			return radius - i
+		} else if i >= n-radius {
+			return i - (n - radius - 1)
+		}
+		return 0
+	}
+
+	side := radius + 1
+	outside := make([]bool, side*side)
+	for dy := 0; dy <= radius; dy++ {
+		for dx := 0; dx <= radius; dx++ {
+			outside[dy*side+dx] = dx*dx+dy*dy > radius*radius
+		}
+	}
+
+	// Only pixels with both dx > 0 and dy > 0 can fall outside the arc.
+	type col struct{ x, dx int }
+	var cols []col
+	for x := 0; x < w; x++ {
+		if dx := edgeDist(x, w); dx > 0 {
+			cols = append(cols, col{x, dx})
+		}
+	}
+	for y := 0; y < h; y++ {
+		dy := edgeDist(y, h)
+		if dy == 0 {
+			continue
+		}
+		row := outside[dy*side:]
+		for _, c := range cols {
+			if row[c.dx] {
+				off := img.PixOffset(b.Min.X+c.x, b.Min.Y+y)
+				img.Pix[off], img.Pix[off+1], img.Pix[off+2], img.Pix[off+3] = 0, 0, 0, 0
+			}
+		}
+	}
+}
+
+// thumbnailFit scales (srcW, srcH) to fit within (maxW, maxH) preserving aspect ratio.
+func thumbnailFit(srcW, srcH, maxW, maxH int) (int, int) {
+	if srcW == 0 || srcH == 0 {
+		return maxW, maxH
+	}
+	newW, newH := maxW, srcH*maxW/srcW
+	if newH > maxH {
+		newH = maxH
+		newW = srcW * maxH / srcH
+	}
+	return newW, newH
+}
+
+// thumbnailCover scales (srcW, srcH) to completely cover (maxW, maxH) preserving aspect ratio.
+// Returns the scaled size and the offset into the scaled image of the centred maxW×maxH crop.
+func thumbnailCover(srcW, srcH, maxW, maxH int) (int, int, image.Point) {
+	if srcW == 0 || srcH == 0 {
+		return maxW, maxH, image.Point{}
+	}
+	newW, newH := maxW, srcH*maxW/srcW
+	if newH < maxH {
+		newH = maxH
+		newW = srcW * maxH / srcH
+	}
+	return newW, newH, image.Point{X: (newW - maxW) / 2, Y: (newH - maxH) / 2}
+}
+
+// normalizeM3ULine returns the first entry of a shortcut .m3u with CR/LF line endings and
+// surrounding spaces removed, so files edited on Windows still resolve.
+func normalizeM3ULine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimLeft(s, "\r\n\t "), "\n")
+	return strings.TrimSpace(strings.ReplaceAll(line, "\r", ""))
+}
+
+// resolveArtSrcPaths returns the places source art for sc is looked for, in priority order:
+// shortcutArtSrcPath, then each of settings.ArtSearchPaths. generateArtworkBg uses the
+// first that exists.
+func resolveArtSrcPaths(sc Shortcut, settings AppSettings) []string {
+	return artSearchCandidates(shortcutArtSrcPath(sc, settings), sc.ConsoleDirName, shortcutArtName(sc), settings)
+}
+
+// shortcutArtName returns the name sc's source art is filed under. ROM art is filed under
+// the ROM's name, so for ROM and save state shortcuts it is the target's file name without
+// extension (the playlist or .cue of a multi-disc or CUE folder is named after the
+// folder), which stays the same when the shortcut is renamed. Tools, and shortcuts whose
+// target can't be read, use the display name.
+func shortcutArtName(sc Shortcut) string {
+	if !sc.IsTool && sc.TargetPath != "" {
+		return stripExtension(strings.TrimSuffix(filepath.Base(sc.TargetPath), ".disabled"))
+	}
+	return sc.Display
+}
+
+// artSearchCandidates returns primary (skipped when "") followed by the user's extra art
+// folders. In each folder <consoleDirName>/<display>.png is tried before <display>.png, so
+// the folders can mirror Roms/ or hold everything flat.
+func artSearchCandidates(primary, consoleDirName, display string, settings AppSettings) []string {
+	var paths []string
+	if primary != "" {
+		paths = append(paths, primary)
+	}
+	for _, dir := range settings.ArtSearchPaths {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(getSDCardRoot(), dir)
+		}
+		if consoleDirName != "" {
+			paths = append(paths, filepath.Join(dir, consoleDirName, display+".png"))
+		}
+		paths = append(paths, filepath.Join(dir, display+".png"))
+	}
+	return paths
+}
+
+// firstExistingPath returns the first of paths that is a regular file. When none is, it
+// returns paths[0] (or "" for none), so callers can still name where art was expected.
+func firstExistingPath(paths []string) string {
+	for _, p := range paths {
+		if fileExists(p) {
+			return p
+		}
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[0]
+}
+
+// shortcutArtSrcPath returns the source artwork PNG path for a shortcut.
+// For tool shortcuts it looks in toolsDir/.media/; for ROM and save state shortcuts it
+// uses the console folder recorded in the marker and shortcutArtName.
+func shortcutArtSrcPath(sc Shortcut, settings AppSettings) string {
+	romsDir, toolsDir, _ := getBasePaths()
+	media := settings.mediaSubdir()
+	if sc.IsTool {
+		return filepath.Join(toolsDir, media, sc.Display+".png")
+	}
+	artName := shortcutArtName(sc)
+	if sc.ConsoleDirName != "" {
+		return filepath.Join(romsDir, sc.ConsoleDirName, media, artName+".png")
+	}
+	// Older markers lack the console folder; read it from the ROM entry instead.
+	_, entry, ok := shortcutROMEntry(sc)
+	if !ok {
+		return ""
+	}
+	// entry is "../Console Dir (TAG)/game.rom" — second component is the console dir.
+	parts := strings.SplitN(entry, "/", 3)
+	if len(parts) < 2 || parts[0] != ".." {
+		return ""
+	}
+	consoleDirName := parts[1]
+	return filepath.Join(romsDir, consoleDirName, media, artName+".png")
+}
+
+// MediaProgress reports how many items a bulk operation has processed: shortcuts for
+// artwork regeneration, ROMs for autoCreateShortcutsForConsole.
+type MediaProgress struct {
+	Completed, Total int
+}
+
+// regenerateMedia regenerates bg.png for each of shortcuts that has source artwork
+// available, creating the media folder if needed.
+// When progress is non-nil, a MediaProgress is sent after each shortcut; the channel is
+// closed when regenerateMedia returns.
+func regenerateMedia(shortcuts []Shortcut, settings AppSettings, progress chan<- MediaProgress) (ArtTotals, error) {
+	if progress != nil {
+		defer close(progress)
+	}
+	var totals ArtTotals
+	for i, sc := range shortcuts {
+		useGlobalBg, forceBlack := settings.forShortcut(sc.Path).artworkBgParams()
+		artSrcs := resolveArtSrcPaths(sc, settings)
+		var stats ArtStats
+		var err error
+		if settings.SkipUserCrafted && isUserCraftedArtwork(sc, firstExistingPath(artSrcs)) {
+			log.Printf("regenerateMedia: %s: skipping user-crafted artwork", sc.Name)
+			stats.SkippedReason = "user-crafted"
+		} else {
+			stats, err = generateArtworkBg(artSrcs, sc.Path, useGlobalBg, forceBlack, settings)
+		}
+		switch {
+		case err != nil:
+			log.Printf("regenerateMedia: %s: %v", sc.Name, err)
+		case stats.SkippedReason != "":
+			log.Printf("regenerateMedia: %s: skipped (%s)", sc.Name, stats.SkippedReason)
+		default:
+			log.Printf("regenerateMedia: %s: %v src=%dB out=%dB", sc.Name, stats.Duration.Round(time.Millisecond), stats.SourceBytes, stats.OutputBytes)
+		}
+		totals.add(stats, err)
+		if progress != nil {
+			progress <- MediaProgress{Completed: i + 1, Total: len(shortcuts)}
+		}
+	}
+	log.Printf("regenerateMedia: shortcuts=%d generated=%d skipped=%d failed=%d time=%v src=%dB out=%dB",
+		len(shortcuts), totals.Generated, totals.Skipped, totals.Failed,
+		totals.Duration.Round(time.Millisecond), totals.SourceBytes, totals.OutputBytes)
+	return totals, nil
+}
+
+// recordGeneratedArt stores the mtime of a bg.png this app just wrote in the shortcut's
+// marker, so isUserCraftedArtwork can tell it apart from a hand-made one.
+func recordGeneratedArt(folderPath string, mtime time.Time) {
+	logError("recordGeneratedArt", updateShortcutMeta(folderPath, func(meta *ShortcutMeta) bool {
+		if meta.DisplayName == "" {
+			return false // no marker yet — nothing to record into
+		}
+		meta.GeneratedArtMTime = mtime
+		return true
+	}))
+}
+
+// isUserCraftedArtwork reports whether the shortcut's bg.png looks hand-made: it is at the
+// screen resolution, newer than the source art (or there is none), and not the file this
+// app last generated. Artwork from versions that didn't record generation counts as
+// hand-made.
+func isUserCraftedArtwork(sc Shortcut, artSrcPath string) bool {
+	info, err := os.Stat(filepath.Join(sc.MediaDir, "bg.png"))
+	if err != nil {
+		return false
+	}
+	if ok, w, _ := validateArtworkDimensions(sc); !ok || w == 0 {
+		return false
+	}
+	if src, err := os.Stat(artSrcPath); err == nil && !info.ModTime().After(src.ModTime()) {
+		return false
+	}
+	// FAT stores mtimes at 2 s resolution, so allow that much drift from the recorded time.
+	meta, _ := readShortcutMeta(sc.Path)
+	drift := info.ModTime().Sub(meta.GeneratedArtMTime)
+	return drift < -2*time.Second || drift > 2*time.Second
+}
+
+// validateArtworkDimensions reports whether the shortcut's .media/bg.png matches
+// screenDimensions, e.g. to catch artwork made on a Smart Pro (1280×720) and copied to a
+// Brick (1024×768). A missing or unreadable bg.png counts as correct, with zero size.
+func validateArtworkDimensions(sc Shortcut) (isCorrect bool, foundW, foundH int) {
+	f, err := os.Open(filepath.Join(sc.MediaDir, "bg.png"))
+	if err != nil {
+		return true, 0, 0
+	}
+	defer f.Close()
+	cfg, err := png.DecodeConfig(f)
+	if err != nil {
+		return true, 0, 0
+	}
+	screenW, screenH := screenDimensions()
+	return cfg.Width == screenW && cfg.Height == screenH, cfg.Width, cfg.Height
+}
+
+// findMismatchedArtwork returns the shortcuts whose bg.png was generated for a different
+// screen size (see validateArtworkDimensions).
+func findMismatchedArtwork(settings AppSettings) ([]Shortcut, error) {
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		return nil, fmt.Errorf("scanning shortcuts: %w", err)
+	}
+	var mismatched []Shortcut
+	for _, sc := range shortcuts {
+		if ok, w, h := validateArtworkDimensions(sc); !ok {
+			log.Printf("findMismatchedArtwork: %s: bg.png is %dx%d", sc.Name, w, h)
+			mismatched = append(mismatched, sc)
+		}
+	}
+	return mismatched, nil
+}
+
+// removeAllMedia removes .media/bg.png (and any bg.jpg) from every existing shortcut.
+// .media itself is removed only when nothing else is left in it; other files placed there
+// by the user are preserved and logged.
+func removeAllMedia(settings AppSettings) error {
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		return fmt.Errorf("scanning shortcuts: %w", err)
+	}
+	preserved := 0
+	for _, sc := range shortcuts {
+		mediaDir := sc.MediaDir
+		removeArtPages(mediaDir, 1)
+		for _, name := range []string{"bg.png", "bg.jpg", "bg.png.tmp"} {
+			bgPath := filepath.Join(mediaDir, name)
+			if err := os.Remove(bgPath); err != nil && !os.IsNotExist(err) {
+				log.Printf("removeAllMedia: remove %s: %v", bgPath, err)
+			}
+		}
+		entries, err := os.ReadDir(mediaDir)
+		if err != nil {
+			continue // no .media dir
+		}
+		if len(entries) > 0 {
+			names := make([]string, len(entries))
+			for i, e := range entries {
+				names[i] = e.Name()
+			}
+			log.Printf("removeAllMedia: %s: preserved %s", sc.Name, strings.Join(names, ", "))
+			preserved++
 			continue
 		}
-		baseName := strings.TrimSuffix(name, ".pak")
-		if isDisabled {
-			baseName = strings.TrimSuffix(strings.TrimSuffix(name, ".disabled"), ".pak")
-		}
-		display := baseName
-		if isDisabled {
-			display += "  [disabled]"
+		if err := os.Remove(mediaDir); err != nil {
+			log.Printf("removeAllMedia: remove %s: %v", mediaDir, err)
 		}
-		tools = append(tools, ToolPak{
-			Name:    baseName,
-			Path:    filepath.Join(toolsDir, name),
-			Display: display,
-		})
 	}
+	log.Printf("removeAllMedia: processed %d shortcuts, %d with other media preserved", len(shortcuts), preserved)
+	return nil
+}
 
-	sort.Slice(tools, func(i, j int) bool {
-		return strings.ToLower(tools[i].Display) < strings.ToLower(tools[j].Display)
-	})
-	log.Printf("scanTools: dir=%s tools=%d", toolsDir, len(tools))
-	return tools, nil
+// checkArtworkConsistency returns the shortcuts without a .media/bg.png while at least one
+// other shortcut of the same type (ROM or Tool) has one.
+func checkArtworkConsistency(settings AppSettings) ([]Shortcut, error) {
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		return nil, fmt.Errorf("scanning shortcuts: %w", err)
+	}
+	hasBg := make([]bool, len(shortcuts))
+	typeHasBg := make(map[bool]bool) // keyed by IsTool
+	for i, sc := range shortcuts {
+		if _, err := os.Stat(filepath.Join(sc.MediaDir, "bg.png")); err == nil {
+			hasBg[i] = true
+			typeHasBg[sc.IsTool] = true
+		}
+	}
+	var missing []Shortcut
+	for i, sc := range shortcuts {
+		if !hasBg[i] && typeHasBg[sc.IsTool] {
+			missing = append(missing, sc)
+		}
+	}
+	log.Printf("checkArtworkConsistency: shortcuts=%d missing=%d", len(shortcuts), len(missing))
+	return missing, nil
 }
 
-// scanShortcuts returns all existing shortcuts.
-func scanShortcuts() ([]Shortcut, error) {
+// artworkZipName is the archive written by exportAllArtwork at the SD card root.
+const artworkZipName = "shortcuts-artwork.zip"
+
+// artworkZipEscaper escapes display names for use as zip folder names: a "/" would
+// otherwise split the entry name, and "%" is escaped so the mapping reverses cleanly.
+var (
+	artworkZipEscaper   = strings.NewReplacer("%", "%25", "/", "%2F")
+	artworkZipUnescaper = strings.NewReplacer("%2F", "/", "%2f", "/", "%25", "%")
+)
+
+// artworkZipPath returns where exportAllArtwork writes and importAllArtwork reads by default.
+func artworkZipPath() string {
 	romsDir, _, _ := getBasePaths()
-	entries, err := os.ReadDir(romsDir)
+	return filepath.Join(filepath.Dir(romsDir), artworkZipName)
+}
+
+// exportAllArtwork writes "<display name>/bg.png" to destZipPath for every shortcut that has
+// a bg.png, with the name escaped by artworkZipEscaper. When two shortcuts share a display
+// name only the first is exported.
+func exportAllArtwork(destZipPath string, settings AppSettings) (int, error) {
+	shortcuts, err := scanShortcuts(settings)
 	if err != nil {
-		return nil, fmt.Errorf("reading roms dir: %w", err)
+		return 0, fmt.Errorf("scanning shortcuts: %w", err)
 	}
+	f, err := os.Create(destZipPath)
+	if err != nil {
+		return 0, fmt.Errorf("creating zip: %w", err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
 
-	var shortcuts []Shortcut
-	for _, e := range entries {
-		if !e.IsDir() {
+	exported := 0
+	seen := make(map[string]bool)
+	for _, sc := range shortcuts {
+		data, err := os.ReadFile(filepath.Join(sc.MediaDir, "bg.png"))
+		if err != nil {
 			continue
 		}
-		fullPath := filepath.Join(romsDir, e.Name())
-		if !isShortcutFolder(fullPath) {
+		if seen[sc.Display] {
+			log.Printf("exportAllArtwork: skipping %s: display name already exported", sc.Name)
 			continue
 		}
-		name := e.Name()
-		tag := extractTag(name)
-		isTool := tag == bridgeEmuTag
-
-		// Read display name from marker file if present; fall back to extracting from folder name.
-		display := readShortcutMarker(fullPath)
-		if display == "" {
-			display = extractDisplayName(name)
-			// Strip ZWS or legacy ★ prefix so the display name is clean.
-			display = strings.TrimPrefix(display, shortcutPrefix)
-			display = strings.TrimPrefix(display, legacyShortcutPrefix)
+		seen[sc.Display] = true
+		w, err := zw.Create(artworkZipEscaper.Replace(sc.Display) + "/bg.png")
+		if err != nil {
+			return exported, fmt.Errorf("adding %s: %w", sc.Display, err)
 		}
-
-		sc := Shortcut{
-			Name:    name,
-			Tag:     tag,
-			Display: display,
-			Path:    fullPath,
-			IsTool:  isTool,
+		if _, err := w.Write(data); err != nil {
+			return exported, fmt.Errorf("writing %s: %w", sc.Display, err)
 		}
+		exported++
+	}
+	if err := zw.Close(); err != nil {
+		return exported, fmt.Errorf("finishing zip: %w", err)
+	}
+	log.Printf("exportAllArtwork: %s: exported=%d", destZipPath, exported)
+	return exported, nil
+}
 
-		// Resolve target
-		if isTool {
-			targetFile := filepath.Join(sc.Path, "target")
-			data, err := os.ReadFile(targetFile)
-			if err == nil {
-				sc.TargetPath = strings.TrimSpace(string(data))
+// importAllArtwork copies each "<display name>/bg.png" entry of srcZipPath into the
+// .media/ folder of the shortcuts with that display name. Entries for shortcuts that no
+// longer exist, entries that are not valid PNGs, and any other files in the archive are
+// skipped. Each bg.png is replaced through writeArtPNG, so a failed write keeps the old
+// file; an entry counts as applied when at least one shortcut received it.
+func importAllArtwork(srcZipPath string, settings AppSettings) (applied, skipped int, err error) {
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		return 0, 0, fmt.Errorf("scanning shortcuts: %w", err)
+	}
+	byDisplay := make(map[string][]Shortcut)
+	for _, sc := range shortcuts {
+		byDisplay[sc.Display] = append(byDisplay[sc.Display], sc)
+	}
+
+	zr, err := zip.OpenReader(srcZipPath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("opening zip: %w", err)
+	}
+	defer zr.Close()
+
+	for _, entry := range zr.File {
+		display, file, ok := strings.Cut(entry.Name, "/")
+		targets := byDisplay[artworkZipUnescaper.Replace(display)]
+		if !ok || file != "bg.png" || len(targets) == 0 {
+			skipped++
+			continue
+		}
+		data, err := readZipEntry(entry)
+		if err != nil {
+			log.Printf("importAllArtwork: %s: %v", entry.Name, err)
+			skipped++
+			continue
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			log.Printf("importAllArtwork: %s: not a PNG: %v", entry.Name, err)
+			skipped++
+			continue
+		}
+		written := 0
+		for _, sc := range targets {
+			mediaDir := sc.MediaDir
+			if err := os.MkdirAll(mediaDir, 0755); err != nil {
+				log.Printf("importAllArtwork: %s: %v", sc.Name, err)
+				continue
 			}
-		} else {
-			m3uFile := filepath.Join(sc.Path, name+".m3u")
-			data, err := os.ReadFile(m3uFile)
-			if err == nil {
-				relPath := strings.TrimSpace(string(data))
-				sc.TargetPath = filepath.Join(sc.Path, relPath)
+			if _, err := writeArtPNG(filepath.Join(mediaDir, "bg.png"), img); err != nil {
+				log.Printf("importAllArtwork: %s: %v", sc.Name, err)
+				continue
 			}
+			written++
 		}
-
-		shortcuts = append(shortcuts, sc)
+		if written == 0 {
+			skipped++
+			continue
+		}
+		applied++
 	}
+	log.Printf("importAllArtwork: %s: applied=%d skipped=%d", srcZipPath, applied, skipped)
+	return applied, skipped, nil
+}
 
-	sort.Slice(shortcuts, func(i, j int) bool {
-		return strings.ToLower(shortcuts[i].Display) < strings.ToLower(shortcuts[j].Display)
-	})
-	log.Printf("scanShortcuts: dir=%s shortcuts=%d", romsDir, len(shortcuts))
-	return shortcuts, nil
+// readZipEntry returns the uncompressed contents of a zip entry.
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
 }
 
-// ── Shortcut creation / removal ──────────────────────────────
+// ── App settings ─────────────────────────────────────────────
 
-// createROMShortcut creates a ROM shortcut folder with m3u and a .shortcut marker.
-// For multi-disc ROMs the m3u points to the playlist inside the game subfolder.
-// For CUE folder ROMs the m3u points to the .cue file inside the game subfolder.
-func createROMShortcut(displayName, tag, consoleDirName string, rom ROMFile, pos ShortcutPosition, settings AppSettings) error {
-	romsDir, _, _ := getBasePaths()
-	folderName := buildFolderName(pos, displayName, tag)
-	folderPath := filepath.Join(romsDir, folderName)
-	log.Printf("createROMShortcut: name=%s tag=%s rom=%s pos=%d multiDisc=%v", displayName, tag, rom.Name, pos, rom.IsMultiDisc)
+// ArtworkMode controls how bg.png is generated for shortcuts.
+const (
+	ArtworkModeBlack     = 0 // Art on black canvas; always writes bg.png (black if no art)
+	ArtworkModeWallpaper = 1 // Art on device wallpaper; always writes bg.png (wallpaper copy if no art)
+	ArtworkModeFallback  = 2 // Art on device wallpaper; skips bg.png entirely when no art exists
+)
 
-	if err := os.MkdirAll(folderPath, 0755); err != nil {
-		return fmt.Errorf("creating shortcut dir: %w", err)
-	}
+// ArtFit controls how source art is scaled into the thumbnail box.
+const (
+	ArtFitFit   = "fit"   // letterbox: whole image visible inside the box (default; "" means fit)
+	ArtFitCover = "cover" // fill the box and centre-crop the overflow; suits square tool icons
+)
 
-	// The relative path from the shortcut folder to the target.
-	// Shortcut folders always sit at the root of romsDir, so the path is
-	// always "../<relativePathFromRomsDir>" — this works for any nesting depth.
-	relFromRoms, _ := filepath.Rel(romsDir, rom.Path)
-	var relPath string
-	switch {
-	case rom.IsMultiDisc:
-		relPath = "../" + relFromRoms + "/" + rom.Name + ".m3u"
-	case rom.IsCueFolder:
-		relPath = "../" + relFromRoms + "/" + rom.Name + ".cue"
-	default:
-		relPath = "../" + relFromRoms
-	}
+// AppSettings holds persistent user preferences.
+type AppSettings struct {
+	CopyArtwork bool    `json:"copy_artwork"`
+	ArtworkMode int     `json:"artwork_mode"` // see ArtworkMode* constants
+	ShowHidden  bool    `json:"show_hidden"`
+	ArtAutoCrop bool    `json:"art_auto_crop"` // trim white/transparent borders from source art
+	ArtSharpen  float64 `json:"art_sharpen"`   // unsharp mask strength after scaling, 0.0 (off)–1.0
+	ArtFit      string  `json:"art_fit"`       // see ArtFit* constants
 
-	m3uPath := filepath.Join(folderPath, folderName+".m3u")
-	if err := os.WriteFile(m3uPath, []byte(relPath), 0644); err != nil {
-		return fmt.Errorf("writing m3u: %w", err)
-	}
+	PinnedTools []string `json:"pinned_tools,omitempty"` // pak base names shown first in the tool picker
 
-	if err := writeShortcutMarker(folderPath, displayName); err != nil {
-		log.Printf("createROMShortcut: warning: could not write marker: %v", err)
-	}
+	// ArtSearchPaths are extra folders searched for source art after the console or Tools
+	// .media/ folder, e.g. ["ArtworkCache"]. Relative paths are from the SD card root.
+	ArtSearchPaths []string `json:"art_search_paths,omitempty"`
 
-	if settings.CopyArtwork {
-		artworkSrc := filepath.Join(filepath.Dir(rom.Path), ".media", rom.Display+".png")
-		useGlobalBg, forceBlack := settings.artworkBgParams()
-		generateArtworkBg(artworkSrc, folderPath, useGlobalBg, forceBlack)
-	}
+	PinnedConsole string `json:"pinned_console,omitempty"` // console folder name Add ROM Shortcut opens directly
 
-	log.Printf("createROMShortcut: created folder=%s", folderPath)
-	return nil
+	ROMSortOrder string `json:"rom_sort_order,omitempty"` // ROM picker order, see ROMSort* constants; alphabetical when empty
+
+	// Consecutive console picks, for offering to pin one (see offerPinConsole).
+	LastConsole       string `json:"last_console,omitempty"`
+	ConsoleStreak     int    `json:"console_streak,omitempty"`
+	PinConsoleOffered bool   `json:"pin_console_offered,omitempty"`
+
+	DisplayNameTemplate string `json:"display_name_template,omitempty"` // e.g. "{ROM} [{TAG}]"; see applyDisplayNameTemplate
+
+	ArtTextOverlay  bool   `json:"art_text_overlay"`             // caption bg.png with the display name
+	ArtTextPosition string `json:"art_text_position,omitempty"`  // see ArtText* constants
+	ArtTextFontPath string `json:"art_text_font_path,omitempty"` // TTF/OTF for the caption; bundled font when empty
+
+	ConsoleOrder []string `json:"console_order,omitempty"` // console Display names listed first in the console picker
+
+	ArtPipeline []string `json:"art_pipeline,omitempty"` // artwork stage names in order; see buildArtPipeline
+
+	ScanSystemTools bool `json:"scan_system_tools"` // also list built-in paks from .system/<platform>/
+	ScanToolsDeep   bool `json:"scan_tools_deep"`   // also find paks in subfolders of Tools/<platform>/
+
+	SkipUserCrafted bool `json:"skip_user_crafted"` // regenerating leaves hand-made bg.png files alone
+	MultiPageArt    bool `json:"multi_page_art"`    // also generate bg2.png, bg3.png, ... from AdditionalArtPaths
+
+	ArtTintGlobalBg bool    `json:"art_tint_global_bg"` // tint the wallpaper with the art's dominant color
+	ArtTintStrength float64 `json:"art_tint_strength"`  // tint overlay opacity, 0.0–1.0
+
+	ScanDepth int `json:"scan_depth"` // ROM subfolder levels to search: 0 = top level only, -1 = all
+
+	MediaSubdir   string `json:"media_subdir,omitempty"`   // artwork folder name; defaultMediaSubdir when empty
+	ConfirmButton string `json:"confirm_button,omitempty"` // button that confirms dialogs, e.g. "B"; A when empty
+
+	// ROMCountCache holds the ROM count per console folder Name shown in the console
+	// picker, computed at ROMCountCacheTime. Written by the first count of a run; see
+	// primeROMCounts.
+	ROMCountCache     map[string]int `json:"rom_count_cache,omitempty"`
+	ROMCountCacheTime time.Time      `json:"rom_count_cache_time,omitzero"`
+
+	// ArtTotals sums every artwork generation so far, for the artwork statistics screen.
+	ArtTotals ArtTotals `json:"art_totals,omitzero"`
+
+	BottomPrefix string `json:"bottom_prefix,omitempty"` // Bottom-position folder prefix; shortcutPrefix when empty
+	TopPrefix    string `json:"top_prefix,omitempty"`    // Top-position folder prefix; topPrefix when empty
+
+	// Prefixes existing folders were last migrated to; see migrateShortcutPrefixes.
+	AppliedBottomPrefix string `json:"applied_bottom_prefix,omitempty"`
+	AppliedTopPrefix    string `json:"applied_top_prefix,omitempty"`
+
+	// extra holds keys from settings.json this version doesn't know (written by a newer
+	// version), so saveSettings can write them back instead of dropping them.
+	extra map[string]json.RawMessage
+
+	SDCARDPath string `json:"sdcard_path,omitempty"` // card root when SDCARD_PATH is unset; see resolveSDCardRoot
 }
 
-// createToolShortcut creates a tool shortcut folder with m3u, target, and a .shortcut marker.
-func createToolShortcut(displayName, pakPath string, pos ShortcutPosition, settings AppSettings) error {
-	romsDir, toolsDir, _ := getBasePaths()
-	folderName := buildFolderName(pos, displayName, bridgeEmuTag)
-	folderPath := filepath.Join(romsDir, folderName)
-	log.Printf("createToolShortcut: name=%s pak=%s pos=%d", displayName, pakPath, pos)
+// defaultDisplayNameTemplate names ROM shortcuts after the ROM alone.
+const defaultDisplayNameTemplate = "{ROM}"
 
-	if err := os.MkdirAll(folderPath, 0755); err != nil {
-		return fmt.Errorf("creating shortcut dir: %w", err)
+// applyDisplayNameTemplate expands the {ROM}, {CONSOLE} and {TAG} tokens in tmpl for the
+// given ROM. An empty template, or one that expands to nothing, yields the ROM display name.
+//
+//	"{ROM} [{TAG}]" → "Battletoads [MD]"
+func applyDisplayNameTemplate(tmpl string, rom ROMFile, console ConsoleDir) string {
+	if tmpl == "" {
+		return rom.Display
+	}
+	name := strings.NewReplacer(
+		"{ROM}", rom.Display,
+		"{CONSOLE}", console.Display,
+		"{TAG}", console.Tag,
+	).Replace(tmpl)
+	if name = strings.TrimSpace(name); name == "" {
+		return rom.Display
 	}
+	return name
+}
 
-	// Write target file containing the .pak path
-	targetPath := filepath.Join(folderPath, "target")
-	if err := os.WriteFile(targetPath, []byte(pakPath), 0644); err != nil {
-		return fmt.Errorf("writing target: %w", err)
+// artworkBgParams returns the (useGlobalBg, forceBlack) arguments for generateArtworkBg.
+func (s AppSettings) artworkBgParams() (useGlobalBg, forceBlack bool) {
+	switch s.ArtworkMode {
+	case ArtworkModeWallpaper:
+		return true, true
+	case ArtworkModeFallback:
+		return true, false
+	default: // ArtworkModeBlack
+		return false, true
 	}
+}
 
-	// Write m3u that points to "target"
-	m3uPath := filepath.Join(folderPath, folderName+".m3u")
-	if err := os.WriteFile(m3uPath, []byte("target"), 0644); err != nil {
-		return fmt.Errorf("writing m3u: %w", err)
+// forShortcut returns s with ArtworkMode replaced by the artwork_mode_override in the
+// marker of the shortcut at folderPath, if it has one.
+func (s AppSettings) forShortcut(folderPath string) AppSettings {
+	if meta, err := readShortcutMeta(folderPath); err == nil && meta.ArtworkModeOverride != nil {
+		s.ArtworkMode = *meta.ArtworkModeOverride
 	}
+	return s
+}
 
-	if err := writeShortcutMarker(folderPath, displayName); err != nil {
-		log.Printf("createToolShortcut: warning: could not write marker: %v", err)
+// getAppDataDir returns the app's shared data directory (settings, registry).
+func getAppDataDir() string {
+	return filepath.Join(getSDCardRoot(), ".userdata", "shared", "Shortcuts")
+}
+
+// macSDCardRoot returns the mock SD card used on macOS: the SDCARDPath setting when set,
+// otherwise ./mock_sdcard. The setting is read from the default card's settings.json, so
+// that file acts as the bootstrap pointing at whichever mock card is under test. It reads
+// the file, so it runs once at startup (resolveSDCardRoot); main exports the result as
+// SDCARD_PATH, which getSDCardRoot returns from then on.
+func macSDCardRoot() string {
+	cwd, _ := os.Getwd()
+	mock := filepath.Join(cwd, "mock_sdcard")
+	if env := os.Getenv("SDCARD_PATH"); env != "" {
+		mock = env
+	}
+	if p := readSDCardPathSetting(mock); p != "" {
+		return p
 	}
+	return mock
+}
 
-	if settings.CopyArtwork {
-		artworkSrc := filepath.Join(toolsDir, ".media", displayName+".png")
-		useGlobalBg, forceBlack := settings.artworkBgParams()
-		generateArtworkBg(artworkSrc, folderPath, useGlobalBg, forceBlack)
+// getSettingsPath returns the path to the settings JSON file.
+func getSettingsPath() string {
+	return filepath.Join(getAppDataDir(), "settings.json")
+}
+
+// loadSettings reads settings from disk. Returns defaults on any error (missing file, parse error).
+func loadSettings() AppSettings {
+	defaults := AppSettings{CopyArtwork: true, ArtworkMode: ArtworkModeWallpaper, ShowHidden: false, ScanDepth: -1}
+	data, err := os.ReadFile(getSettingsPath())
+	if err != nil {
+		return defaults
+	}
+	// Unmarshal over the defaults so keys missing from older files keep their default.
+	s := defaults
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Printf("loadSettings: parse error: %v", err)
+		return defaults
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err == nil {
+		known := settingsJSONKeys()
+		for k, v := range raw {
+			if !known[k] {
+				if s.extra == nil {
+					s.extra = make(map[string]json.RawMessage)
+				}
+				s.extra[k] = v
+			}
+		}
 	}
+	return s
+}
 
-	log.Printf("createToolShortcut: created folder=%s", folderPath)
-	return nil
+// settingsJSONKeys returns the JSON keys of the AppSettings fields.
+func settingsJSONKeys() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(AppSettings{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			name = f.Name
+		}
+		keys[name] = true
+	}
+	return keys
 }
 
-// removeShortcut removes a shortcut folder entirely.
-func removeShortcut(shortcutPath string) error {
-	log.Printf("removeShortcut: path=%s", shortcutPath)
-	return os.RemoveAll(shortcutPath)
+// saveSettings persists settings to disk.
+func saveSettings(s AppSettings) error {
+	path := getSettingsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings dir: %w", err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshalling settings: %w", err)
+	}
+	if len(s.extra) > 0 {
+		// Merge back unknown keys from a newer version; known fields always win.
+		var merged map[string]json.RawMessage
+		if err := json.Unmarshal(data, &merged); err != nil {
+			return fmt.Errorf("marshalling settings: %w", err)
+		}
+		for k, v := range s.extra {
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+		if data, err = json.Marshal(merged); err != nil {
+			return fmt.Errorf("marshalling settings: %w", err)
+		}
+	}
+	return os.WriteFile(path, data, 0644)
 }
 
-// ── Bridge emu management ────────────────────────────────────
+// utf8BOM is the byte order mark some Windows editors prepend to UTF-8 text files.
+const utf8BOM = "\xEF\xBB\xBF"
 
-const bridgeLaunchScript = "#!/bin/sh\n# SHORTCUT.pak - Bridge emulator for tool shortcuts.\nTARGET=$(cat \"$1\")\nif [ -x \"$TARGET/launch.sh\" ]; then\n    exec \"$TARGET/launch.sh\"\nfi\n"
+// ShortcutMeta is the content of the .shortcut marker file, stored as JSON. Markers written
+// by older versions hold only the display name as plain text; readShortcutMeta accepts both.
+type ShortcutMeta struct {
+	DisplayName    string    `json:"display_name"`
+	ConsoleDirName string    `json:"console_dir_name,omitempty"` // ROM shortcuts: console folder in Roms/
+	CorePath       string    `json:"core_path,omitempty"`        // ROM shortcuts: the ROM's .core override, else the tag's core; see coreForTag
+	AccessCount    int       `json:"access_count,omitempty"`     // launches seen by refreshAccessStats
+	LastAccessed   time.Time `json:"last_accessed,omitzero"`     // .m3u mtime at the last refresh
+	CreatedAt      time.Time `json:"created_at,omitzero"`        // when this app created the shortcut; zero for older markers
+	Description    string    `json:"description,omitempty"`      // tool shortcuts: pak.json description at creation
+	Version        string    `json:"version,omitempty"`          // tool shortcuts: pak.json version at creation
 
-// ensureBridgeEmu makes sure SHORTCUT.pak exists for tool shortcuts.
-func ensureBridgeEmu() {
-	if platform == PlatformMac {
-		return // not needed on macOS
-	}
+	TargetMissingSince time.Time `json:"target_missing_since,omitzero"` // first time findStaleShortcuts saw the target gone
+	GeneratedArtMTime  time.Time `json:"generated_art_mtime,omitzero"`  // bg.png mtime when this app last wrote it
 
-	_, _, emusDir := getBasePaths()
-	pakDir := filepath.Join(emusDir, "SHORTCUT.pak")
-	launchPath := filepath.Join(pakDir, "launch.sh")
+	AdditionalArtPaths []string `json:"additional_art_paths,omitempty"` // sources for bg2.png, bg3.png, ... (see generateArtPages)
 
-	if _, err := os.Stat(launchPath); err == nil {
-		log.Printf("ensureBridgeEmu: already present at %s", launchPath)
-		return // already exists
-	}
+	ArtworkModeOverride *int `json:"artwork_mode_override,omitempty"` // ArtworkMode* for this shortcut; nil uses the global setting
+}
 
-	if err := os.MkdirAll(pakDir, 0755); err != nil {
-		logError("creating SHORTCUT.pak dir", err)
-		return
+// readShortcutMeta reads the .shortcut marker in folderPath. A leading UTF-8 BOM (from
+// markers edited on Windows) is stripped; content not starting with "{" is treated as a
+// legacy plain-text display name.
+func readShortcutMeta(folderPath string) (ShortcutMeta, error) {
+	data, err := os.ReadFile(filepath.Join(folderPath, shortcutMarkerFile))
+	if err != nil {
+		return ShortcutMeta{}, err
+	}
+	text := strings.TrimSpace(strings.TrimPrefix(string(data), utf8BOM))
+	if !strings.HasPrefix(text, "{") {
+		return ShortcutMeta{DisplayName: text}, nil
+	}
+	var meta ShortcutMeta
+	if err := json.Unmarshal([]byte(text), &meta); err != nil {
+		return ShortcutMeta{}, fmt.Errorf("parsing marker: %w", err)
 	}
+	meta.DisplayName = strings.TrimSpace(meta.DisplayName)
+	return meta, nil
+}
 
-	if err := os.WriteFile(launchPath, []byte(bridgeLaunchScript), 0755); err != nil {
-		logError("writing SHORTCUT.pak launch.sh", err)
-		return
+// readShortcutMarker returns the display name for the shortcut at folderPath, as
+// readAndValidateShortcutMarker does, without the error.
+func readShortcutMarker(folderPath string, prefixes sortPrefixes) string {
+	meta, _ := readAndValidateShortcutMarker(folderPath, prefixes)
+	return meta.DisplayName
+}
+
+// readAndValidateShortcutMarker reads the .shortcut marker in folderPath. When the marker
+// is missing, unreadable or its name fails validateMarkerName (e.g. a file left full of NUL
+// bytes by a write cut short), DisplayName is taken from the folder name, with the sort
+// prefix in prefixes removed, and the reason is returned as the error.
+func readAndValidateShortcutMarker(folderPath string, prefixes sortPrefixes) (ShortcutMeta, error) {
+	meta, err := readShortcutMeta(folderPath)
+	if err == nil {
+		err = validateMarkerName(meta.DisplayName)
 	}
+	if err == nil {
+		return meta, nil
+	}
+	name := filepath.Base(folderPath)
+	_, prefix := prefixes.match(name)
+	meta.DisplayName = folderDisplayName(name, prefix)
+	if !os.IsNotExist(err) {
+		log.Printf("readAndValidateShortcutMarker: warning: %s: %v; using %q from the folder name", name, err, meta.DisplayName)
+	}
+	return meta, err
+}
 
-	log.Printf("ensureBridgeEmu: created at %s", launchPath)
+// validateMarkerName checks a display name read from a marker: it must be non-empty,
+// valid UTF-8 and free of NUL bytes.
+func validateMarkerName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("marker has no display name")
+	case strings.ContainsRune(name, 0):
+		return errors.New("marker display name contains NUL bytes")
+	case !utf8.ValidString(name):
+		return errors.New("marker display name is not valid UTF-8")
+	}
+	return nil
 }
 
-// ── String utilities ─────────────────────────────────────────
+// markerMu serializes writes to .shortcut markers. The startup goroutine updates launch
+// stats and missing-target times (refreshAccessStats, findStaleShortcuts) while the UI may
+// be changing other fields of the same markers.
+var markerMu sync.Mutex
 
-// isHidden checks if a name should be hidden (dotfiles, .disabled, map.txt).
-func isHidden(name string) bool {
-	return strings.HasPrefix(name, ".") ||
-		strings.HasSuffix(name, ".disabled") ||
-		name == "map.txt"
+// writeShortcutMeta writes meta to the .shortcut marker in folderPath, replacing it whole.
+// Use it for new shortcuts; changes to an existing marker go through updateShortcutMeta so
+// a concurrent update to another field isn't lost.
+func writeShortcutMeta(folderPath string, meta ShortcutMeta) error {
+	markerMu.Lock()
+	defer markerMu.Unlock()
+	return writeMarkerFile(folderPath, meta)
 }
 
-// isMacDotfile returns true for dot-prefixed names that are Mac/system artifacts
-// rather than user-created hidden content. Used when ShowHidden is on to avoid
-// surfacing .DS_Store, .Spotlight-V100, etc. while still showing user-hidden folders.
-func isMacDotfile(name string) bool {
-	if !strings.HasPrefix(name, ".") {
-		return false
+// updateShortcutMeta reads the marker in folderPath, lets fn change it and writes it back,
+// holding markerMu throughout. A missing marker starts out empty. When fn returns false
+// nothing is written.
+func updateShortcutMeta(folderPath string, fn func(meta *ShortcutMeta) bool) error {
+	markerMu.Lock()
+	defer markerMu.Unlock()
+	meta, err := readShortcutMeta(folderPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading marker: %w", err)
+	}
+	if !fn(&meta) {
+		return nil
+	}
+	return writeMarkerFile(folderPath, meta)
+}
+
+// writeMarkerFile writes meta to a uniquely named temp file in folderPath and renames it
+// over the marker, so readers never see a partial marker. Callers hold markerMu.
+func writeMarkerFile(folderPath string, meta ShortcutMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("marshalling marker: %w", err)
+	}
+	f, err := os.CreateTemp(folderPath, shortcutMarkerFile+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
 	}
-	// Any dot-dir that also has a (TAG) suffix is likely a user-created hidden console —
-	// show it. Everything else (no tag) is treated as system/Mac cruft.
-	return extractTag(name) == ""
+	if err == nil {
+		err = os.Rename(tmpPath, filepath.Join(folderPath, shortcutMarkerFile))
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+	}
+	return err
 }
 
-// dirHasVisibleContent reports whether dir contains at least one entry that is not hidden.
-// Used to skip empty or dot-file-only console folders when ShowHidden is off.
-func dirHasVisibleContent(path string) bool {
-	entries, err := os.ReadDir(path)
+// refreshAccessStats approximates launch counts: NextUI touches a shortcut's .m3u when it
+// launches it, so an mtime newer than the marker's LastAccessed counts as one launch. The
+// first refresh of a shortcut only records the baseline mtime. Returns how many markers
+// were updated.
+func refreshAccessStats(settings AppSettings) (int, error) {
+	shortcuts, err := scanShortcuts(settings)
 	if err != nil {
-		return false
+		return 0, fmt.Errorf("scanning shortcuts: %w", err)
 	}
-	for _, e := range entries {
-		if !isHidden(e.Name()) {
+	updated := 0
+	for _, sc := range shortcuts {
+		info, err := os.Stat(filepath.Join(sc.Path, sc.Name+".m3u"))
+		if err != nil {
+			continue
+		}
+		mtime := info.ModTime()
+		changed := false
+		err = updateShortcutMeta(sc.Path, func(meta *ShortcutMeta) bool {
+			if meta.DisplayName == "" {
+				meta.DisplayName = sc.Display
+			}
+			switch {
+			case meta.LastAccessed.IsZero():
+				// First sighting — record the baseline only.
+			case mtime.After(meta.LastAccessed):
+				meta.AccessCount++
+			default:
+				return false
+			}
+			meta.LastAccessed = mtime
+			changed = true
 			return true
+		})
+		if err != nil {
+			log.Printf("refreshAccessStats: %s: %v", sc.Name, err)
+			continue
+		}
+		if changed {
+			updated++
 		}
 	}
-	return false
-}
-
-// isShortcutFolder checks if a full folder path is a shortcut.
-// Detects: ZWS-prefixed folders (Bottom), legacy ★-prefixed folders, and folders
-// with a .shortcut marker file (Top/Alpha positions).
-func isShortcutFolder(folderPath string) bool {
-	name := filepath.Base(folderPath)
-	if strings.HasPrefix(name, shortcutPrefix) || strings.HasPrefix(name, legacyShortcutPrefix) {
-		return true
-	}
-	_, err := os.Stat(filepath.Join(folderPath, shortcutMarkerFile))
-	return err == nil
+	log.Printf("refreshAccessStats: shortcuts=%d updated=%d", len(shortcuts), updated)
+	return updated, nil
 }
 
-// extractTag extracts the emulator tag from a directory name.
-// e.g. "Game Boy Advance (GBA)" -> "GBA", "\u200BBattletoads (MD)" -> "MD"
-func extractTag(name string) string {
-	idx := strings.LastIndex(name, "(")
-	if idx < 0 {
+// checkSortPrefixes returns s with each configured Top or Bottom prefix that can't be used
+// cleared, so the default applies, and a line describing each one cleared. A prefix can't
+// be used when it is only whitespace, or when a console folder in Roms/ starts with it:
+// that console would be taken for a shortcut, hidden from the console picker and renamed
+// by migrateShortcutPrefixes.
+func checkSortPrefixes(s AppSettings) (AppSettings, []string) {
+	romsDir, _, _ := getBasePaths()
+	entries, _ := os.ReadDir(romsDir)
+	check := func(setting, prefix string) string {
+		if prefix == "" {
+			return ""
+		}
+		if strings.TrimSpace(prefix) == "" {
+			return fmt.Sprintf("%s %q is only whitespace", setting, prefix)
+		}
+		for _, e := range entries {
+			if !e.IsDir() || !strings.HasPrefix(e.Name(), prefix) {
+				continue
+			}
+			dir := filepath.Join(romsDir, e.Name())
+			if !fileExists(filepath.Join(dir, shortcutMarkerFile)) && !fileExists(filepath.Join(dir, e.Name()+".m3u")) {
+				return fmt.Sprintf("%s %q matches the console folder %q", setting, prefix, e.Name())
+			}
+		}
 		return ""
 	}
-	end := strings.LastIndex(name, ")")
-	if end <= idx {
-		return ""
+	var problems []string
+	if p := check("bottom_prefix", s.BottomPrefix); p != "" {
+		problems = append(problems, p)
+		s.BottomPrefix = ""
+	}
+	if p := check("top_prefix", s.TopPrefix); p != "" {
+		problems = append(problems, p)
+		s.TopPrefix = ""
 	}
-	return strings.TrimSpace(name[idx+1 : end])
+	return s, problems
 }
 
-// extractDisplayName extracts the display name, stripping the trailing (TAG).
-// e.g. "\u200BBattletoads (MD)" -> "\u200BBattletoads"
-func extractDisplayName(name string) string {
-	idx := strings.LastIndex(name, "(")
-	if idx < 0 {
-		return name
-	}
-	return strings.TrimSpace(name[:idx])
+// sortPrefixesChanged reports whether the prefixes configured in s differ from the ones
+// existing folders were last migrated to. Before the first migration the built-in
+// defaults count as applied.
+func sortPrefixesChanged(s AppSettings) bool {
+	applied := AppSettings{TopPrefix: s.AppliedTopPrefix, BottomPrefix: s.AppliedBottomPrefix}.sortPrefixes()
+	current := s.sortPrefixes()
+	return applied.Top != current.Top || applied.Bottom != current.Bottom
 }
 
-// stripExtension removes the file extension from a filename.
-func stripExtension(name string) string {
-	ext := filepath.Ext(name)
-	if len(ext) >= 2 && len(ext) <= 5 {
-		return strings.TrimSuffix(name, ext)
-	}
-	return name
+// prefixRename is a shortcut folder migrateShortcutPrefixes moves to the current prefix.
+type prefixRename struct {
+	sc      Shortcut
+	newName string
 }
 
-// buildFolderName constructs the shortcut folder name for the given position.
-//
-//	Bottom: "\u200BBattletoads (World) (MD)"  (invisible ZWS prefix, sorts after Z)
-//	Top:    "0) Battletoads (World) (MD)"
-//	Alpha:  "Battletoads (World) (MD)"
-func buildFolderName(pos ShortcutPosition, displayName, tag string) string {
-	base := fmt.Sprintf("%s (%s)", displayName, tag)
-	switch pos {
-	case ShortcutPositionTop:
-		return topPrefix + base
-	case ShortcutPositionAlpha:
-		return base
-	default: // ShortcutPositionBottom — ZWS needs no space separator
-		return shortcutPrefix + base
+// pendingPrefixRenames returns the Top and Bottom shortcuts that still use an old prefix (a
+// previously configured one or the built-in default), with their folder name under the
+// current prefix. Legacy ★ folders are left alone.
+func pendingPrefixRenames(shortcuts []Shortcut, prefixes sortPrefixes) []prefixRename {
+	var renames []prefixRename
+	for _, sc := range shortcuts {
+		pos, prefix := prefixes.match(sc.Name)
+		var current string
+		switch pos {
+		case ShortcutPositionTop:
+			current = prefixes.Top
+		case ShortcutPositionBottom:
+			current = prefixes.Bottom
+		default:
+			continue
+		}
+		if prefix == current || prefix == legacyShortcutPrefix {
+			continue
+		}
+		renames = append(renames, prefixRename{sc: sc, newName: current + strings.TrimPrefix(sc.Name, prefix)})
 	}
+	return renames
 }
 
-// generateArtworkBg composites a fullscreen bg.png for a shortcut's .media/ folder.
-// When useGlobalBg is true the device's global /mnt/SDCARD/bg.png is used as the base layer;
-// otherwise the canvas is plain black. The art is then overlaid right-aligned at the NextUI
-// SCREEN_GAMELIST thumbnail dimensions (screen_w*0.45 × screen_h*0.60).
-// When forceBlack is true a bg.png is written even when artSrcPath does not exist (base layer
-// only, no art overlay). When forceBlack is false and artSrcPath is missing, nothing is written.
-func generateArtworkBg(artSrcPath, destFolder string, useGlobalBg, forceBlack bool) {
-	var artImg image.Image
-	if _, err := os.Stat(artSrcPath); err == nil {
-		img, err := loadPNGImage(artSrcPath)
-		if err != nil {
-			log.Printf("generateArtworkBg: load art: %v", err)
-			return
+// migrateShortcutPrefixes renames the folders in renames (see pendingPrefixRenames), then
+// records the current prefixes of settings as applied. Returns the number of folders
+// renamed. NextUI's recents and saves made through a shortcut follow its old folder name,
+// so sortPrefixStartupFlow asks before calling this.
+func migrateShortcutPrefixes(settings AppSettings, renames []prefixRename) (int, error) {
+	renamed := 0
+	for _, r := range renames {
+		if err := renameShortcutFolder(r.sc, r.newName); err != nil {
+			log.Printf("migrateShortcutPrefixes: %s: %v", r.sc.Name, err)
+			continue
 		}
-		artImg = img
-	} else if !forceBlack {
-		return // no art and not forcing — skip silently
+		renamed++
 	}
 
-	screenW, screenH := screenDimensions()
-	canvas := image.NewNRGBA(image.Rect(0, 0, screenW, screenH))
-
-	// Fill with black (fallback when global bg.png is absent or doesn't cover).
-	pix := canvas.Pix
-	for i := 0; i < len(pix); i += 4 {
-		pix[i], pix[i+1], pix[i+2], pix[i+3] = 0x00, 0x00, 0x00, 0xff
-	}
-
-	// Layer 1: global bg.png scaled to cover the canvas (centre-crop, no letterbox).
-	// Skipped when useGlobalBg is false — canvas stays plain black.
-	if useGlobalBg {
-		bgPath := globalBgPath()
-		if bgImg, err := loadPNGImage(bgPath); err == nil {
-			srcW, srcH := bgImg.Bounds().Dx(), bgImg.Bounds().Dy()
-			scaleX := float64(screenW) / float64(srcW)
-			scaleY := float64(screenH) / float64(srcH)
-			scale := scaleX
-			if scaleY > scaleX {
-				scale = scaleY
-			}
-			newW := int(float64(srcW) * scale)
-			newH := int(float64(srcH) * scale)
-			scaledBg := image.NewNRGBA(image.Rect(0, 0, newW, newH))
-			xdraw.BiLinear.Scale(scaledBg, scaledBg.Bounds(), bgImg, bgImg.Bounds(), xdraw.Src, nil)
-			// Centre-crop: offset into scaledBg so the canvas window is centred.
-			offX := (newW - screenW) / 2
-			offY := (newH - screenH) / 2
-			xdraw.Draw(canvas, canvas.Bounds(), scaledBg, image.Point{offX, offY}, xdraw.Src)
-		}
-	}
-
-	// Layer 2: game/tool art — mirrors nextui.c SCREEN_GAMELIST thumbnail rendering:
-	//   max_w = screen_w * CFG_DEFAULT_GAMEARTWIDTH (0.45)
-	//   max_h = screen_h * 0.60
-	//   target_x = screen_w - new_w - SCALE1(BUTTON_MARGIN*3)  [30 px at FIXED_SCALE=2]
-	//   center_y = screen_h*0.50 - new_h/2
-	// Skipped when artImg is nil (forceBlack mode with no source art).
-	if artImg != nil {
-		maxW := int(float64(screenW) * 0.45)
-		maxH := int(float64(screenH) * 0.60)
-		artW, artH := thumbnailFit(artImg.Bounds().Dx(), artImg.Bounds().Dy(), maxW, maxH)
-		scaledArt := image.NewNRGBA(image.Rect(0, 0, artW, artH))
-		xdraw.BiLinear.Scale(scaledArt, scaledArt.Bounds(), artImg, artImg.Bounds(), xdraw.Over, nil)
-		// Rounded corners: effective radius = FIXED_SCALE(2) * CFG_DEFAULT_THUMBRADIUS(20) = 40 px.
-		// Mirrors GFX_ApplyRoundedCorners_8888 in nextui: pixels where dx²+dy²>r² become transparent.
-		applyRoundedCorners(scaledArt, 40)
-
-		const rightMargin = 30 // SCALE1(BUTTON_MARGIN * 3) at FIXED_SCALE=2
-		targetX := max(0, screenW-artW-rightMargin)
-		centerY := screenH/2 - artH/2
-		artDst := image.Rect(targetX, centerY, targetX+artW, centerY+artH)
-		xdraw.Draw(canvas, artDst, scaledArt, image.Point{}, xdraw.Over)
+	prefixes := settings.sortPrefixes()
+	if settings.AppliedBottomPrefix != prefixes.Bottom || settings.AppliedTopPrefix != prefixes.Top {
+		settings.AppliedBottomPrefix, settings.AppliedTopPrefix = prefixes.Bottom, prefixes.Top
+		if err := saveSettings(settings); err != nil {
+			return renamed, fmt.Errorf("saving settings: %w", err)
+		}
+	}
+	if renamed > 0 {
+		logError("updating shortcut registry", updateShortcutRegistry(settings))
 	}
+	log.Printf("migrateShortcutPrefixes: pending=%d renamed=%d", len(renames), renamed)
+	return renamed, nil
+}
 
-	// Save composite.
-	mediaDir := filepath.Join(destFolder, ".media")
-	if err := os.MkdirAll(mediaDir, 0755); err != nil {
-		log.Printf("generateArtworkBg: mkdir .media: %v", err)
-		return
+// renameShortcutFolder renames a shortcut folder in Roms/ and the .m3u inside it, which
+// must match the folder name. Fails if newName is already taken by another folder; a
+// case-only rename is allowed even though FAT reports the old folder under the new name.
+func renameShortcutFolder(sc Shortcut, newName string) error {
+	newPath := filepath.Join(filepath.Dir(sc.Path), newName)
+	if _, err := os.Stat(newPath); err == nil && !strings.EqualFold(newName, sc.Name) {
+		return fmt.Errorf("%q already exists", newName)
 	}
-	f, err := os.Create(filepath.Join(mediaDir, "bg.png"))
-	if err != nil {
-		log.Printf("generateArtworkBg: create bg.png: %v", err)
-		return
+	if err := os.Rename(sc.Path, newPath); err != nil {
+		return fmt.Errorf("renaming folder: %w", err)
 	}
-	defer f.Close()
-	if err := png.Encode(f, canvas); err != nil {
-		log.Printf("generateArtworkBg: encode: %v", err)
+	oldM3U := filepath.Join(newPath, sc.Name+".m3u")
+	if err := os.Rename(oldM3U, filepath.Join(newPath, newName+".m3u")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("renaming m3u: %w", err)
 	}
-	log.Printf("generateArtworkBg: %s/.media/bg.png (%dx%d)", destFolder, screenW, screenH)
+	log.Printf("renameShortcutFolder: %q -> %q", sc.Name, newName)
+	return nil
 }
 
-// screenDimensions returns the native screen size for the current platform.
-// isBrick is set from DEVICE="brick" (exported by NextUI's launch.sh).
-//
-//	Smart Pro (DEVICE=smartpro) → 1280×720
-//	Smart Pro S (tg5050)        → 1280×720
-//	Brick       (DEVICE=brick)  → 1024×768
-func screenDimensions() (int, int) {
-	if isBrick {
-		return 1024, 768
+// renameShortcut gives sc a new display name: its folder and .m3u are renamed with
+// renameShortcutFolder, keeping the position prefix and tag, and the marker is updated.
+// Returns the shortcut as it is after the rename.
+func renameShortcut(sc Shortcut, newDisplay string, prefixes sortPrefixes) (Shortcut, error) {
+	newName := buildFolderName(sc.Position, newDisplay, sc.Tag, prefixes)
+	if newName != sc.Name {
+		if err := renameShortcutFolder(sc, newName); err != nil {
+			return sc, err
+		}
+		sc.Path = filepath.Join(filepath.Dir(sc.Path), newName)
+		sc.MediaDir = filepath.Join(sc.Path, filepath.Base(sc.MediaDir))
+		sc.Name = newName
+	}
+	err := updateShortcutMeta(sc.Path, func(meta *ShortcutMeta) bool {
+		meta.DisplayName = newDisplay
+		return true
+	})
+	if err != nil {
+		return sc, fmt.Errorf("updating marker: %w", err)
 	}
-	return 1280, 720
+	sc.Display = newDisplay
+	return sc, nil
 }
 
-// globalBgPath returns the path to the device's global background image.
-func globalBgPath() string {
-	sdcard := os.Getenv("SDCARD_PATH")
-	if sdcard == "" {
-		if platform == PlatformMac {
-			cwd, _ := os.Getwd()
-			return filepath.Join(cwd, "mock_sdcard", "bg.png")
+// findDuplicateTargets groups shortcuts by TargetPath and returns the groups with more than
+// one shortcut. Shortcuts with an unresolved target are ignored. Each group keeps the
+// scanShortcuts order (by display name).
+func findDuplicateTargets(settings AppSettings) (map[string][]Shortcut, error) {
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		return nil, fmt.Errorf("scanning shortcuts: %w", err)
+	}
+	groups := make(map[string][]Shortcut)
+	for _, sc := range shortcuts {
+		// A save state shortcut shares its ROM with the plain shortcut on purpose.
+		if sc.TargetPath == "" || sc.IsSaveState {
+			continue
 		}
-		return "/mnt/SDCARD/bg.png"
+		key := filepath.Clean(sc.TargetPath)
+		groups[key] = append(groups[key], sc)
 	}
-	return filepath.Join(sdcard, "bg.png")
+	for key, group := range groups {
+		if len(group) < 2 {
+			delete(groups, key)
+		}
+	}
+	log.Printf("findDuplicateTargets: shortcuts=%d duplicateGroups=%d", len(shortcuts), len(groups))
+	return groups, nil
 }
 
-// loadPNGImage opens and decodes a PNG file.
-func loadPNGImage(path string) (image.Image, error) {
-	f, err := os.Open(path)
+// staleShortcutDays is how long a target must be missing before the health check offers to
+// remove its shortcut, so a card swapped out briefly doesn't flag everything.
+const staleShortcutDays = 7
+
+// findStaleShortcuts returns shortcuts whose target has been missing for at least
+// maxAgeDays days (0 = any missing target). The first time a target is seen missing the
+// time is recorded in the marker; it is cleared again if the target comes back.
+// Shortcuts whose target could not be read are ignored, and so are ROM shortcuts whose
+// console folder is gone (see consoleDirAbsent): they are not stale, and removing them
+// would lose shortcuts that work again once the console is enabled or its rename fixed.
+func findStaleShortcuts(maxAgeDays int, settings AppSettings) ([]Shortcut, error) {
+	shortcuts, err := scanShortcuts(settings)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("scanning shortcuts: %w", err)
 	}
-	defer f.Close()
-	return png.Decode(f)
-}
+	romsDir, _, _ := getBasePaths()
+	now := time.Now()
+	maxAge := time.Duration(maxAgeDays) * 24 * time.Hour
 
-// applyRoundedCorners sets pixels outside the corner arcs to fully transparent.
-// Ports NextUI's GFX_ApplyRoundedCorners_8888: for each corner, pixels where
-// dx²+dy² > radius² (dx/dy = distance past the corner edge) are zeroed.
-func applyRoundedCorners(img *image.NRGBA, radius int) {
-	b := img.Bounds()
-	w, h := b.Dx(), b.Dy()
-	if radius <= 0 || w == 0 || h == 0 {
-		return
-	}
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			dx := 0
-			if x < radius {
-				dx = radius - x
-			} else if x >= w-radius {
-				dx = x - (w - radius - 1)
-			}
-			dy := 0
-			if y < radius {
-				dy = radius - y
-			} else if y >= h-radius {
-				dy = y - (h - radius - 1)
-			}
-			if dx*dx+dy*dy > radius*radius {
-				off := img.PixOffset(x, y)
-				img.Pix[off], img.Pix[off+1], img.Pix[off+2], img.Pix[off+3] = 0, 0, 0, 0
+	var stale []Shortcut
+	for _, sc := range shortcuts {
+		if sc.TargetPath == "" {
+			continue
+		}
+		_, statErr := os.Stat(sc.TargetPath)
+		missing := os.IsNotExist(statErr)
+		if missing && consoleDirAbsent(sc, romsDir) {
+			continue
+		}
+		if recorded := !sc.TargetMissingSince.IsZero(); missing != recorded {
+			// Startup and the health check can both run this; re-check under the marker lock
+			// so only the first records the change.
+			err := updateShortcutMeta(sc.Path, func(meta *ShortcutMeta) bool {
+				if recorded := !meta.TargetMissingSince.IsZero(); missing == recorded {
+					sc.TargetMissingSince = meta.TargetMissingSince
+					return false
+				}
+				if meta.DisplayName == "" {
+					meta.DisplayName = sc.Display
+				}
+				meta.TargetMissingSince = time.Time{}
+				if missing {
+					meta.TargetMissingSince = now
+				}
+				sc.TargetMissingSince = meta.TargetMissingSince
+				return true
+			})
+			if err != nil {
+				log.Printf("findStaleShortcuts: %s: %v", sc.Name, err)
+				continue
 			}
 		}
+		if missing && now.Sub(sc.TargetMissingSince) >= maxAge {
+			stale = append(stale, sc)
+		}
 	}
+	log.Printf("findStaleShortcuts: shortcuts=%d stale=%d maxAgeDays=%d", len(shortcuts), len(stale), maxAgeDays)
+	return stale, nil
 }
 
-// thumbnailFit scales (srcW, srcH) to fit within (maxW, maxH) preserving aspect ratio.
-func thumbnailFit(srcW, srcH, maxW, maxH int) (int, int) {
-	if srcW == 0 || srcH == 0 {
-		return maxW, maxH
-	}
-	newW, newH := maxW, srcH*maxW/srcW
-	if newH > maxH {
-		newH = maxH
-		newW = srcW * maxH / srcH
-	}
-	return newW, newH
+// MissingConsoleDir is a console folder that ROM shortcuts point into but that no longer
+// exists in Roms/, usually because it was renamed. See findMissingConsoleDirs.
+type MissingConsoleDir struct {
+	Name      string // old folder name, e.g. "Game Boy Advance (GBA)"
+	Shortcuts int    // ROM shortcuts whose .m3u points into it
 }
 
-// shortcutArtSrcPath returns the source artwork PNG path for a shortcut.
-// For tool shortcuts it looks in toolsDir/.media/; for ROM shortcuts it reads
-// the shortcut's .m3u to determine which console folder owns the artwork.
-func shortcutArtSrcPath(sc Shortcut) string {
-	romsDir, toolsDir, _ := getBasePaths()
+// shortcutROMEntry returns the file in sc naming its ROM and that ROM as an .m3u entry,
+// "../<console dir>/<path>": the .m3u itself, or for save state shortcuts shortcutROMFile,
+// where an absolute path from an older shortcut is made relative to Roms/. ok is false for
+// tool shortcuts and unreadable files.
+func shortcutROMEntry(sc Shortcut) (file, entry string, ok bool) {
 	if sc.IsTool {
-		return filepath.Join(toolsDir, ".media", sc.Display+".png")
+		return "", "", false
+	}
+	if !sc.IsSaveState {
+		file = filepath.Join(sc.Path, sc.Name+".m3u")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", "", false
+		}
+		return file, normalizeM3ULine(string(data)), true
 	}
-	// Read the m3u inside the shortcut folder to find the console directory.
-	m3uPath := filepath.Join(sc.Path, sc.Name+".m3u")
-	data, err := os.ReadFile(m3uPath)
+	file = filepath.Join(sc.Path, shortcutROMFile)
+	data, err := os.ReadFile(file)
 	if err != nil {
-		return ""
+		return "", "", false
 	}
-	relPath := strings.TrimSpace(string(data))
-	// relPath is "../Console Dir (TAG)/game.rom" — second component is the console dir.
-	parts := strings.SplitN(relPath, "/", 3)
-	if len(parts) < 2 || parts[0] != ".." {
-		return ""
+	line := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(line) {
+		return file, normalizeM3ULine(line), true
 	}
-	consoleDirName := parts[1]
-	return filepath.Join(romsDir, consoleDirName, ".media", sc.Display+".png")
+	romsDir, _, _ := getBasePaths()
+	rel, err := filepath.Rel(romsDir, line)
+	if err != nil {
+		return "", "", false
+	}
+	return file, "../" + filepath.ToSlash(rel), true
 }
 
-// regenerateAllMedia regenerates bg.png for every existing shortcut that has
-// source artwork available, creating .media/ if needed.
-func regenerateAllMedia(settings AppSettings) error {
-	shortcuts, err := scanShortcuts()
-	if err != nil {
-		return fmt.Errorf("scanning shortcuts: %w", err)
+// splitM3UTarget splits a ROM shortcut's .m3u entry "../<console dir>/<rest>" into the
+// console folder and the path inside it. ok is false for entries of any other shape.
+func splitM3UTarget(line string) (consoleDir, rest string, ok bool) {
+	parts := strings.SplitN(line, "/", 3)
+	if len(parts) < 3 || parts[0] != ".." || parts[1] == "" || parts[2] == "" {
+		return "", "", false
 	}
-	useGlobalBg, forceBlack := settings.artworkBgParams()
-	for _, sc := range shortcuts {
-		artSrc := shortcutArtSrcPath(sc)
-		generateArtworkBg(artSrc, sc.Path, useGlobalBg, forceBlack)
+	return parts[1], parts[2], true
+}
+
+// consoleDirAbsent reports whether sc is a ROM shortcut whose console folder is missing
+// from romsDir under the name its .m3u uses: the console was disabled (renamed to
+// "<name>.disabled") or renamed, which the health check's console rename fix repairs.
+func consoleDirAbsent(sc Shortcut, romsDir string) bool {
+	_, entry, ok := shortcutROMEntry(sc)
+	if !ok {
+		return false
 	}
-	log.Printf("regenerateAllMedia: processed %d shortcuts", len(shortcuts))
-	return nil
+	consoleDir, _, ok := splitM3UTarget(entry)
+	return ok && !dirExists(filepath.Join(romsDir, consoleDir))
 }
 
-// removeAllMedia removes .media/bg.png from every existing shortcut.
-func removeAllMedia() error {
-	shortcuts, err := scanShortcuts()
+// findMissingConsoleDirs returns the console folders referenced by ROM shortcuts that are
+// missing from Roms/, sorted by name.
+func findMissingConsoleDirs(settings AppSettings) ([]MissingConsoleDir, error) {
+	shortcuts, err := scanShortcuts(settings)
 	if err != nil {
-		return fmt.Errorf("scanning shortcuts: %w", err)
+		return nil, fmt.Errorf("scanning shortcuts: %w", err)
 	}
+	romsDir, _, _ := getBasePaths()
+
+	counts := make(map[string]int)
 	for _, sc := range shortcuts {
-		bgPath := filepath.Join(sc.Path, ".media", "bg.png")
-		if err := os.Remove(bgPath); err != nil && !os.IsNotExist(err) {
-			log.Printf("removeAllMedia: remove %s: %v", bgPath, err)
+		_, entry, ok := shortcutROMEntry(sc)
+		if !ok {
+			continue
 		}
-		// Remove .media dir if it is now empty.
-		_ = os.Remove(filepath.Join(sc.Path, ".media"))
+		consoleDir, _, ok := splitM3UTarget(entry)
+		if !ok || dirExists(filepath.Join(romsDir, consoleDir)) {
+			continue
+		}
+		counts[consoleDir]++
 	}
-	log.Printf("removeAllMedia: processed %d shortcuts", len(shortcuts))
-	return nil
-}
 
-// ── App settings ─────────────────────────────────────────────
+	missing := make([]MissingConsoleDir, 0, len(counts))
+	for name, n := range counts {
+		missing = append(missing, MissingConsoleDir{Name: name, Shortcuts: n})
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Name < missing[j].Name })
+	log.Printf("findMissingConsoleDirs: shortcuts=%d missing=%d", len(shortcuts), len(missing))
+	return missing, nil
+}
 
-// ArtworkMode controls how bg.png is generated for shortcuts.
-const (
-	ArtworkModeBlack    = 0 // Art on black canvas; always writes bg.png (black if no art)
-	ArtworkModeWallpaper = 1 // Art on device wallpaper; always writes bg.png (wallpaper copy if no art)
-	ArtworkModeFallback  = 2 // Art on device wallpaper; skips bg.png entirely when no art exists
-)
+// updateShortcutsForConsoleDirChange points ROM shortcuts whose .m3u (or, for save state
+// shortcuts, shortcutROMFile) references the console folder oldName at newName instead,
+// and updates console_dir_name in their markers. A shortcut is only rewritten when its
+// target exists under newName; the others are logged and left alone.
+func updateShortcutsForConsoleDirChange(oldName, newName string, settings AppSettings) (updated int, err error) {
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		return 0, fmt.Errorf("scanning shortcuts: %w", err)
+	}
+	romsDir, _, _ := getBasePaths()
 
-// AppSettings holds persistent user preferences.
-type AppSettings struct {
-	CopyArtwork bool `json:"copy_artwork"`
-	ArtworkMode int  `json:"artwork_mode"` // see ArtworkMode* constants
-	ShowHidden  bool `json:"show_hidden"`
-}
+	for _, sc := range shortcuts {
+		file, entry, ok := shortcutROMEntry(sc)
+		if !ok {
+			continue
+		}
+		consoleDir, rest, ok := splitM3UTarget(entry)
+		if !ok || consoleDir != oldName {
+			continue
+		}
+		newTarget := filepath.Join(romsDir, newName, filepath.FromSlash(rest))
+		if _, err := os.Stat(newTarget); err != nil {
+			log.Printf("updateShortcutsForConsoleDirChange: %s: %s not found in %s, skipping", sc.Name, rest, newName)
+			continue
+		}
+		data := "../" + newName + "/" + rest
+		if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+			log.Printf("updateShortcutsForConsoleDirChange: %s: writing %s: %v", sc.Name, filepath.Base(file), err)
+			continue
+		}
+		logError("updateShortcutsForConsoleDirChange: "+sc.Name, updateShortcutMeta(sc.Path, func(meta *ShortcutMeta) bool {
+			if meta.ConsoleDirName != "" && meta.ConsoleDirName != oldName {
+				return false
+			}
+			if meta.DisplayName == "" {
+				meta.DisplayName = sc.Display
+			}
+			meta.ConsoleDirName = newName
+			return true
+		}))
+		updated++
+	}
 
-// artworkBgParams returns the (useGlobalBg, forceBlack) arguments for generateArtworkBg.
-func (s AppSettings) artworkBgParams() (useGlobalBg, forceBlack bool) {
-	switch s.ArtworkMode {
-	case ArtworkModeWallpaper:
-		return true, true
-	case ArtworkModeFallback:
-		return true, false
-	default: // ArtworkModeBlack
-		return false, true
+	if updated > 0 {
+		logError("updating shortcut registry", updateShortcutRegistry(settings))
 	}
+	log.Printf("updateShortcutsForConsoleDirChange: %q -> %q updated=%d", oldName, newName, updated)
+	return updated, nil
 }
 
-// getSettingsPath returns the path to the settings JSON file.
-func getSettingsPath() string {
-	sdcard := os.Getenv("SDCARD_PATH")
-	if sdcard == "" {
-		if platform == PlatformMac {
-			cwd, _ := os.Getwd()
-			sdcard = filepath.Join(cwd, "mock_sdcard")
-		} else {
-			sdcard = "/mnt/SDCARD"
+// trashDirName is the folder in Roms/ where removeShortcut moves deleted shortcuts, and
+// where they are restored from. It is dot-prefixed, so NextUI never lists it.
+const trashDirName = ".trash"
+
+// findTrashedShortcut returns the path of a shortcut for displayName and tag in Roms/.trash/
+// under any position prefix, or "" if there is none.
+func findTrashedShortcut(displayName, tag string, prefixes sortPrefixes) string {
+	romsDir, _, _ := getBasePaths()
+	trashDir := filepath.Join(romsDir, trashDirName)
+	for _, pos := range []ShortcutPosition{ShortcutPositionBottom, ShortcutPositionTop, ShortcutPositionAlpha} {
+		folderPath := filepath.Join(trashDir, buildFolderName(pos, displayName, tag, prefixes))
+		if info, err := os.Stat(folderPath); err == nil && info.IsDir() {
+			return folderPath
 		}
 	}
-	return filepath.Join(sdcard, ".userdata", "shared", "Shortcuts", "settings.json")
+	return ""
 }
 
-// loadSettings reads settings from disk. Returns defaults on any error (missing file, parse error).
-func loadSettings() AppSettings {
-	defaults := AppSettings{CopyArtwork: true, ArtworkMode: ArtworkModeWallpaper, ShowHidden: false}
-	data, err := os.ReadFile(getSettingsPath())
-	if err != nil {
-		return defaults
+// discardTrashedShortcut permanently deletes a shortcut folder in Roms/.trash/.
+func discardTrashedShortcut(trashedPath string) error {
+	log.Printf("discardTrashedShortcut: path=%s", trashedPath)
+	return os.RemoveAll(trashedPath)
+}
+
+// restoreTrashedShortcut moves a shortcut folder from Roms/.trash/ back into Roms/.
+func restoreTrashedShortcut(trashedPath string) error {
+	romsDir, _, _ := getBasePaths()
+	dest := filepath.Join(romsDir, filepath.Base(trashedPath))
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%q already exists", filepath.Base(dest))
 	}
-	var s AppSettings
-	if err := json.Unmarshal(data, &s); err != nil {
-		log.Printf("loadSettings: parse error: %v", err)
-		return defaults
+	if err := os.Rename(trashedPath, dest); err != nil {
+		return fmt.Errorf("restoring shortcut: %w", err)
 	}
-	return s
+	log.Printf("restoreTrashedShortcut: %s -> %s", trashedPath, dest)
+	return nil
 }
 
-// saveSettings persists settings to disk.
-func saveSettings(s AppSettings) error {
-	path := getSettingsPath()
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating settings dir: %w", err)
-	}
-	data, err := json.Marshal(s)
-	if err != nil {
-		return fmt.Errorf("marshalling settings: %w", err)
+// romTargetPath returns the file a ROM shortcut's .m3u points at: the ROM itself, or the
+// playlist or .cue inside a multi-disc or CUE folder.
+func romTargetPath(rom ROMFile) string {
+	switch {
+	case rom.IsMultiDisc:
+		return filepath.Join(rom.Path, rom.Name+".m3u")
+	case rom.IsCueFolder:
+		return filepath.Join(rom.Path, rom.Name+".cue")
+	default:
+		return rom.Path
 	}
-	return os.WriteFile(path, data, 0644)
 }
 
-// readShortcutMarker reads the clean display name stored in the .shortcut marker file.
-// Returns "" if the file does not exist or cannot be read.
-func readShortcutMarker(folderPath string) string {
-	data, err := os.ReadFile(filepath.Join(folderPath, shortcutMarkerFile))
-	if err != nil {
-		return ""
+// romM3UEntry returns rom as written to a ROM shortcut's .m3u (and a save state shortcut's
+// shortcutROMFile): the path of romTargetPath relative to the shortcut folder. Shortcut
+// folders always sit at the root of romsDir, so it is "../<path from romsDir>" at any
+// nesting depth, and keeps working when the card is mounted elsewhere.
+func romM3UEntry(romsDir string, rom ROMFile) string {
+	relFromRoms, _ := filepath.Rel(romsDir, romTargetPath(rom))
+	return "../" + relFromRoms
+}
+
+// existingShortcutTarget looks for a shortcut for displayName and tag at any position, like
+// shortcutExists, and returns the target its .m3u resolves to. target is "" when the
+// shortcut exists but its .m3u can't be read.
+func existingShortcutTarget(displayName, tag string, prefixes sortPrefixes) (target string, found bool) {
+	romsDir, _, _ := getBasePaths()
+	for _, pos := range []ShortcutPosition{ShortcutPositionBottom, ShortcutPositionTop, ShortcutPositionAlpha} {
+		folderName := buildFolderName(pos, displayName, tag, prefixes)
+		folderPath := filepath.Join(romsDir, folderName)
+		if _, err := os.Stat(folderPath); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(folderPath, folderName+".m3u"))
+		if err != nil {
+			return "", true
+		}
+		return filepath.Join(folderPath, normalizeM3ULine(string(data))), true
 	}
-	return strings.TrimSpace(string(data))
+	return "", false
 }
 
-// writeShortcutMarker writes the clean display name to the .shortcut marker file
-// inside the given shortcut folder.
-func writeShortcutMarker(folderPath, displayName string) error {
-	markerPath := filepath.Join(folderPath, shortcutMarkerFile)
-	return os.WriteFile(markerPath, []byte(displayName), 0644)
+// disambiguatedName suggests a display name for rom when displayName is taken by a shortcut
+// to another ROM: displayName plus the first parenthesized group of the ROM's file name it
+// doesn't already contain (usually the region, e.g. "Final Fantasy VI (Japan)"), else plus
+// the file extension.
+func disambiguatedName(displayName string, rom ROMFile) string {
+	rest := rom.Name
+	for {
+		open := strings.Index(rest, "(")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(rest[open:], ")")
+		if end < 0 {
+			break
+		}
+		group := rest[open : open+end+1]
+		if !strings.Contains(displayName, group) {
+			return displayName + " " + group
+		}
+		rest = rest[open+end+1:]
+	}
+	if ext := strings.TrimPrefix(filepath.Ext(rom.Name), "."); ext != "" && !rom.IsMultiDisc && !rom.IsCueFolder {
+		return displayName + " (" + ext + ")"
+	}
+	return displayName + " 2"
 }
 
 // shortcutExists checks if a shortcut already exists for the given display name and tag
-// under any of the three position prefixes.
-func shortcutExists(displayName, tag string) bool {
+// under any of the three position prefixes, in Roms/ or in Roms/.trash/. A trashed one
+// counts so a new shortcut never clashes with restoring it (see offerTrashRestore).
+func shortcutExists(displayName, tag string, prefixes sortPrefixes) bool {
+	return shortcutInRoms(displayName, tag, prefixes) || findTrashedShortcut(displayName, tag, prefixes) != ""
+}
+
+// shortcutInRoms is shortcutExists without the Roms/.trash/ check.
+func shortcutInRoms(displayName, tag string, prefixes sortPrefixes) bool {
 	romsDir, _, _ := getBasePaths()
 	for _, pos := range []ShortcutPosition{ShortcutPositionBottom, ShortcutPositionTop, ShortcutPositionAlpha} {
-		folderPath := filepath.Join(romsDir, buildFolderName(pos, displayName, tag))
+		folderPath := filepath.Join(romsDir, buildFolderName(pos, displayName, tag, prefixes))
 		if _, err := os.Stat(folderPath); err == nil {
 			return true
 		}
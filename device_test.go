@@ -0,0 +1,594 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBridgeResourceMatchesScript(t *testing.T) {
+	data, err := os.ReadFile("resources/SHORTCUT.pak/launch.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []Platform{PlatformTG5040, PlatformTG5050} {
+		if want := bridgeLaunchScriptForPlatform(p); string(data) != want {
+			t.Errorf("resources/SHORTCUT.pak/launch.sh differs from bridgeLaunchScriptForPlatform(%s):\n%s", p, want)
+		}
+	}
+}
+
+func TestIsMacDotfile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"._Mario.smc", true},
+		{"._Roms", true},
+		{".__MACOSX", true},
+		{"._Game Boy (GB)", true}, // AppleDouble even with a tag
+		{".DS_Store", true},
+		{".Spotlight-V100", true},
+		{".Hidden Console (GBA)", false},
+		{"Mario.smc", false},
+	}
+	for _, tt := range tests {
+		if got := isMacDotfile(tt.name); got != tt.want {
+			t.Errorf("isMacDotfile(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// edgeImage returns a w×h opaque image, dark grey left of x = w/2 and light grey from
+// there on. Neither is at the limit, so sharpening can push both sides apart.
+func edgeImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{R: 60, G: 60, B: 60, A: 0xff}
+			if x >= w/2 {
+				c = color.NRGBA{R: 200, G: 200, B: 200, A: 0xff}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestSharpenImageEdge(t *testing.T) {
+	orig := edgeImage(8, 8)
+	sharp := edgeImage(8, 8)
+	sharpenImage(sharp, 1.0)
+
+	// The pixels either side of the edge move apart; flat areas are left alone.
+	for _, x := range []int{3, 4} {
+		o, s := orig.NRGBAAt(x, 4), sharp.NRGBAAt(x, 4)
+		if diff := max(absDiff(o.R, s.R), absDiff(o.G, s.G), absDiff(o.B, s.B)); diff < 1 {
+			t.Errorf("edge pixel x=%d unchanged by sharpening: %v", x, s)
+		}
+	}
+	for _, x := range []int{0, 7} {
+		if o, s := orig.NRGBAAt(x, 4), sharp.NRGBAAt(x, 4); o != s {
+			t.Errorf("flat pixel x=%d changed: %v -> %v", x, o, s)
+		}
+	}
+}
+
+func absDiff(a, b uint8) int {
+	return max(int(a), int(b)) - min(int(a), int(b))
+}
+
+// applyRoundedCornersLoop is applyRoundedCorners before the lookup table: the distance
+// test for every pixel. Kept as the reference for the table version.
+func applyRoundedCornersLoop(img *image.NRGBA, radius int) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if radius <= 0 || w == 0 || h == 0 {
+		return
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx := 0
+			if x < radius {
+				dx = radius - x
+			} else if x >= w-radius {
+				dx = x - (w - radius - 1)
+			}
+			dy := 0
+			if y < radius {
+				dy = radius - y
+			} else if y >= h-radius {
+				dy = y - (h - radius - 1)
+			}
+			if dx*dx+dy*dy > radius*radius {
+				off := img.PixOffset(x, y)
+				img.Pix[off], img.Pix[off+1], img.Pix[off+2], img.Pix[off+3] = 0, 0, 0, 0
+			}
+		}
+	}
+}
+
+func TestApplyRoundedCornersMatchesLoop(t *testing.T) {
+	for _, radius := range []int{1, 5, 40} {
+		got, want := edgeImage(128, 96), edgeImage(128, 96)
+		applyRoundedCorners(got, radius)
+		applyRoundedCornersLoop(want, radius)
+		if string(got.Pix) != string(want.Pix) {
+			t.Errorf("radius %d: lookup table result differs from the per-pixel loop", radius)
+		}
+	}
+}
+
+func BenchmarkApplyRoundedCornersLoop(b *testing.B) {
+	img := edgeImage(1280, 720)
+	for b.Loop() {
+		applyRoundedCornersLoop(img, 40)
+	}
+}
+
+func BenchmarkApplyRoundedCornersTable(b *testing.B) {
+	img := edgeImage(1280, 720)
+	for b.Loop() {
+		applyRoundedCorners(img, 40)
+	}
+}
+
+func TestReadShortcutMarkerStripsBOM(t *testing.T) {
+	for _, content := range []string{
+		"\xEF\xBB\xBFBattletoads\n",
+		"\xEF\xBB\xBF{\"display_name\": \"Battletoads\"}",
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, shortcutMarkerFile), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := readShortcutMarker(dir, AppSettings{}.sortPrefixes()); got != "Battletoads" {
+			t.Errorf("readShortcutMarker(%q) = %q, want %q", content, got, "Battletoads")
+		}
+	}
+}
+
+func TestReadAndValidateShortcutMarkerFallback(t *testing.T) {
+	prefixes := AppSettings{}.sortPrefixes()
+	dir := filepath.Join(t.TempDir(), shortcutPrefix+"Battletoads (MD)")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	marker := `{"display_name": "Battle\u0000toads", "access_count": 3}`
+	if err := os.WriteFile(filepath.Join(dir, shortcutMarkerFile), []byte(marker), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := readAndValidateShortcutMarker(dir, prefixes)
+	if err == nil {
+		t.Error("expected an error for a display name with a NUL byte")
+	}
+	if meta.DisplayName != "Battletoads" {
+		t.Errorf("DisplayName = %q, want %q from the folder name", meta.DisplayName, "Battletoads")
+	}
+	if meta.AccessCount != 3 {
+		t.Errorf("AccessCount = %d, want the marker's 3", meta.AccessCount)
+	}
+}
+
+func TestSettingsKeepUnknownKeys(t *testing.T) {
+	t.Setenv("SDCARD_PATH", t.TempDir())
+	path := getSettingsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Written by a newer version: one known key, two this version doesn't have.
+	in := `{"show_hidden": true, "future_flag": true, "future_colors": {"accent": "#ff8800"}}`
+	if err := os.WriteFile(path, []byte(in), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := loadSettings()
+	if !s.ShowHidden {
+		t.Fatal("known key show_hidden was not loaded")
+	}
+	s.ShowHidden = false
+	if err := saveSettings(s); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out["future_flag"]); got != "true" {
+		t.Errorf("future_flag = %s, want true", got)
+	}
+	if got := string(out["future_colors"]); got != `{"accent":"#ff8800"}` {
+		t.Errorf("future_colors = %s, want {\"accent\":\"#ff8800\"}", got)
+	}
+	if got := string(out["show_hidden"]); got != "false" {
+		t.Errorf("show_hidden = %s, want the saved false", got)
+	}
+	if reloaded := loadSettings(); reloaded.ShowHidden {
+		t.Error("show_hidden true after reload, want false")
+	}
+}
+
+// limitedWriter passes the first n bytes through, then fails like a card pulled mid-write.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+var errWriteCutShort = errors.New("write cut short")
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > l.n {
+		n, _ := l.w.Write(p[:l.n])
+		l.n = 0
+		return n, errWriteCutShort
+	}
+	l.n -= len(p)
+	return l.w.Write(p)
+}
+
+func TestEncodeArtPNGKeepsOldFileOnFailedWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bg.png")
+	if err := encodePNGFile(path, edgeImage(16, 16)); err != nil {
+		t.Fatal(err)
+	}
+	old, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pngEncode = func(w io.Writer, m image.Image) error {
+		return png.Encode(&limitedWriter{w: w, n: 64}, m)
+	}
+	t.Cleanup(func() { pngEncode = png.Encode })
+
+	if _, err := encodeArtPNG(path, edgeImage(64, 64)); !errors.Is(err, errWriteCutShort) {
+		t.Fatalf("encodeArtPNG error = %v, want %v", err, errWriteCutShort)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, old) {
+		t.Error("bg.png changed by a failed write")
+	}
+	if _, err := png.Decode(bytes.NewReader(got)); err != nil {
+		t.Errorf("bg.png no longer decodes: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("bg.png.tmp left behind (stat err = %v)", err)
+	}
+}
+
+func TestExtractTagSkipsRegions(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Super Mario World (SNES) (USA)", "SNES"},
+		{"Super Mario World (USA) (SNES)", "SNES"},
+		{"Game Boy Advance (GBA)", "GBA"},
+		{"\u200BBattletoads (MD)", "MD"},
+		{"Tetris (World) (Rev 1) (GB) (Europe)", "GB"},
+		{"Only A Region (USA)", "USA"}, // nothing else to pick
+		{"No Tag", ""},
+	}
+	for _, tt := range tests {
+		if got := extractTag(tt.name); got != tt.want {
+			t.Errorf("extractTag(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtractDisplayNameMatchesTag(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Game (USA) (GBA)", "Game (USA)"},
+		{"Super Mario World (SNES) (USA)", "Super Mario World (USA)"},
+		{"Tetris (2) (GB)", "Tetris (2)"},
+		{"\u200BBattletoads (MD)", "\u200BBattletoads"},
+		{"Only A Region (USA)", "Only A Region"},
+		{"No Tag", "No Tag"},
+	}
+	for _, tt := range tests {
+		if got := extractDisplayName(tt.name); got != tt.want {
+			t.Errorf("extractDisplayName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		// The display name plus the tag extractTag found rebuilds the same folder.
+		if tag := extractTag(tt.name); tag != "" && extractDisplayName(tt.want+" ("+tag+")") != tt.want {
+			t.Errorf("extractDisplayName(%q) does not round-trip with tag %q", tt.want+" ("+tag+")", tag)
+		}
+	}
+}
+
+func TestScreenDimensions(t *testing.T) {
+	tests := []struct {
+		platform     Platform
+		brick        bool
+		wantW, wantH int
+	}{
+		{PlatformTG3040, false, 1024, 768},
+		{PlatformTG3040, true, 1024, 768},
+		{PlatformTG5040, false, 1280, 720}, // Smart Pro
+		{PlatformTG5040, true, 1024, 768},  // Brick
+		{PlatformTG5050, false, 1280, 720},
+		{PlatformTG5050, true, 1280, 720}, // no Brick variant; isBrick is ignored
+		{PlatformMac, false, 1280, 720},
+		{PlatformMac, true, 1024, 768},
+	}
+	savedPlatform, savedBrick := platform, isBrick
+	t.Cleanup(func() { platform, isBrick = savedPlatform, savedBrick })
+	for _, tt := range tests {
+		platform, isBrick = tt.platform, tt.brick
+		if w, h := screenDimensions(); w != tt.wantW || h != tt.wantH {
+			t.Errorf("screenDimensions() on %s (brick=%v) = %d×%d, want %d×%d", tt.platform, tt.brick, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestUpdateShortcutMetaConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeShortcutMeta(dir, ShortcutMeta{DisplayName: "Battletoads"}); err != nil {
+		t.Fatal(err)
+	}
+	const n = 50
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			err := updateShortcutMeta(dir, func(meta *ShortcutMeta) bool {
+				meta.AccessCount++
+				if i == n/2 {
+					meta.ConsoleDirName = "Sega Genesis (MD)"
+				}
+				return true
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	meta, err := readShortcutMeta(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.AccessCount != n || meta.ConsoleDirName != "Sega Genesis (MD)" || meta.DisplayName != "Battletoads" {
+		t.Errorf("after %d concurrent updates got %+v", n, meta)
+	}
+	if tmps, _ := filepath.Glob(filepath.Join(dir, shortcutMarkerFile+".*.tmp")); len(tmps) > 0 {
+		t.Errorf("temp files left behind: %v", tmps)
+	}
+}
+
+func TestArtworkZipNameRoundTrip(t *testing.T) {
+	for _, display := range []string{"Battletoads", "AC/DC Rocks", "100% Orange Juice", "a%2Fb"} {
+		escaped := artworkZipEscaper.Replace(display)
+		if strings.Contains(escaped, "/") {
+			t.Errorf("%q escaped to %q, which still contains a slash", display, escaped)
+		}
+		if got := artworkZipUnescaper.Replace(escaped); got != display {
+			t.Errorf("%q round-tripped to %q", display, got)
+		}
+	}
+}
+
+func TestBridgePakDirsOnlyExistingSystemEmus(t *testing.T) {
+	card := t.TempDir()
+	t.Setenv("SDCARD_PATH", card)
+	savedPlatform := platform
+	t.Cleanup(func() { platform = savedPlatform })
+	platform = PlatformTG5040
+
+	userPak := filepath.Join(card, "Emus", "tg5040", "SHORTCUT.pak")
+	if got := bridgePakDirs("SHORTCUT"); len(got) != 1 || got[0] != userPak {
+		t.Fatalf("bridgePakDirs without .system = %v, want [%s]", got, userPak)
+	}
+
+	emulators := filepath.Join(card, ".system", "tg5040", "emulators")
+	if err := os.MkdirAll(emulators, 0755); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{userPak, filepath.Join(emulators, "SHORTCUT.pak")}
+	if got := bridgePakDirs("SHORTCUT"); !slices.Equal(got, want) {
+		t.Errorf("bridgePakDirs with .system/tg5040/emulators = %v, want %v", got, want)
+	}
+
+	systemEmus := filepath.Join(card, ".system", "tg5040", "paks", "Emus")
+	if err := os.MkdirAll(systemEmus, 0755); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{userPak, filepath.Join(systemEmus, "SHORTCUT.pak"), filepath.Join(emulators, "SHORTCUT.pak")}
+	if got := bridgePakDirs("SHORTCUT"); !slices.Equal(got, want) {
+		t.Errorf("bridgePakDirs = %v, want %v", got, want)
+	}
+}
+
+func TestScanROMsCoreSidecar(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Fire Red.gba":  "rom",
+		"Fire Red.CORE": " mgba\n",
+		"Emerald.gba":   "rom",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	roms, err := scanROMs(dir, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, r := range roms {
+		got[r.Name] = r.CoreOverride
+	}
+	want := map[string]string{"Fire Red.gba": "mgba", "Emerald.gba": ""}
+	if !maps.Equal(got, want) {
+		t.Errorf("ROM core overrides = %v, want %v", got, want)
+	}
+}
+
+func TestCheckSortPrefixes(t *testing.T) {
+	card := t.TempDir()
+	t.Setenv("SDCARD_PATH", card)
+	savedPlatform := platform
+	t.Cleanup(func() { platform = savedPlatform })
+	platform = PlatformTG5040
+
+	romsDir := filepath.Join(card, "Roms")
+	for _, dir := range []string{"GB Game Boy (GB)", "#Pinned Game (GBA)"} {
+		if err := os.MkdirAll(filepath.Join(romsDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A shortcut already using the prefix doesn't count as a console folder.
+	if err := os.WriteFile(filepath.Join(romsDir, "#Pinned Game (GBA)", "#Pinned Game (GBA).m3u"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, problems := checkSortPrefixes(AppSettings{TopPrefix: "#", BottomPrefix: "GB"})
+	if s.TopPrefix != "#" || s.BottomPrefix != "" || len(problems) != 1 {
+		t.Errorf("checkSortPrefixes kept top=%q bottom=%q, problems %v; want top \"#\" only", s.TopPrefix, s.BottomPrefix, problems)
+	}
+
+	s, problems = checkSortPrefixes(AppSettings{TopPrefix: "  ", BottomPrefix: "z"})
+	if s.TopPrefix != "" || s.BottomPrefix != "z" || len(problems) != 1 {
+		t.Errorf("checkSortPrefixes kept top=%q bottom=%q, problems %v; want bottom \"z\" only", s.TopPrefix, s.BottomPrefix, problems)
+	}
+	if p := (AppSettings{TopPrefix: " "}).sortPrefixes(); p.Top != topPrefix {
+		t.Errorf("whitespace top prefix resolved to %q, want the default", p.Top)
+	}
+}
+
+func TestResolveNameConflict(t *testing.T) {
+	romsDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(romsDir, "Tetris (GB)"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := resolveNameConflict(romsDir, "Tetris (GB)"); err != nil || got != "Tetris (2) (GB)" {
+		t.Errorf("resolveNameConflict = %q, %v; want \"Tetris (2) (GB)\"", got, err)
+	}
+
+	// A Stat error other than "not found" must not be retried forever.
+	notDir := filepath.Join(romsDir, "file")
+	if err := os.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveNameConflict(notDir, "Tetris (GB)"); err == nil {
+		t.Error("resolveNameConflict under a file returned no error")
+	}
+}
+
+func TestPrimeROMCountsUsesFreshCache(t *testing.T) {
+	card := t.TempDir()
+	t.Setenv("SDCARD_PATH", card)
+	t.Cleanup(func() { storeROMCounts(nil, time.Time{}) })
+
+	gba := filepath.Join(card, "Roms", "Game Boy Advance (GBA)")
+	if err := os.MkdirAll(gba, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.gba", "b.gba"} {
+		if err := os.WriteFile(filepath.Join(gba, name), []byte("rom"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	consoles := []ConsoleDir{
+		{Name: "Super Nintendo (SFC)", Path: filepath.Join(card, "Roms", "Super Nintendo (SFC)")},
+		{Name: "Game Boy Advance (GBA)", Path: gba},
+	}
+	settings := AppSettings{
+		ScanDepth:         -1,
+		ROMCountCache:     map[string]int{"Super Nintendo (SFC)": 7},
+		ROMCountCacheTime: time.Now().Add(-time.Minute),
+	}
+
+	primeROMCounts(consoles, settings)
+	counts, fresh := cachedROMCounts(consoles)
+	want := map[string]int{"Super Nintendo (SFC)": 7, "Game Boy Advance (GBA)": 2}
+	if !fresh || !maps.Equal(counts, want) {
+		t.Errorf("cachedROMCounts = %v, fresh=%v; want %v, fresh", counts, fresh, want)
+	}
+	if fileExists(getSettingsPath()) {
+		t.Error("primeROMCounts saved settings although the cache was fresh")
+	}
+}
+
+func TestShortcutArtNameFollowsROM(t *testing.T) {
+	tests := []struct {
+		sc   Shortcut
+		want string
+	}{
+		{Shortcut{Display: "My Favourite", TargetPath: "/Roms/GBA/Fire Red (USA).gba"}, "Fire Red (USA)"},
+		{Shortcut{Display: "FF7", TargetPath: "/Roms/PS/Final Fantasy VII/Final Fantasy VII.m3u"}, "Final Fantasy VII"},
+		{Shortcut{Display: "Old", TargetPath: "/Roms/GB/Tetris.gb.disabled"}, "Tetris"},
+		{Shortcut{Display: "Slot 2", IsSaveState: true, TargetPath: "/Roms/GBA/Emerald.gba"}, "Emerald"},
+		{Shortcut{Display: "Files", IsTool: true, TargetPath: "/Tools/tg5040/Files.pak"}, "Files"},
+		{Shortcut{Display: "Unreadable"}, "Unreadable"},
+	}
+	for _, tt := range tests {
+		if got := shortcutArtName(tt.sc); got != tt.want {
+			t.Errorf("shortcutArtName(%q) = %q, want %q", tt.sc.Display, got, tt.want)
+		}
+	}
+}
+
+func TestRemoveShortcutMovesToTrash(t *testing.T) {
+	card := t.TempDir()
+	t.Setenv("SDCARD_PATH", card)
+	prefixes := AppSettings{}.sortPrefixes()
+
+	folder := filepath.Join(card, "Roms", buildFolderName(ShortcutPositionAlpha, "Tetris", "GB", prefixes))
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeShortcut(folder); err != nil {
+		t.Fatal(err)
+	}
+	if dirExists(folder) {
+		t.Error("shortcut folder still in Roms/")
+	}
+	if findTrashedShortcut("Tetris", "GB", prefixes) == "" {
+		t.Error("findTrashedShortcut found nothing after removeShortcut")
+	}
+	if !shortcutExists("Tetris", "GB", prefixes) {
+		t.Error("shortcutExists = false for a trashed shortcut")
+	}
+}
+
+func TestPickToolListIndex(t *testing.T) {
+	tools := []ToolPak{{Path: "/t/A.pak"}, {Path: "/t/B.pak"}, {Path: "/t/C.pak"}}
+	tests := []struct {
+		pinned []string
+		path   string
+		want   int
+	}{
+		{nil, "/t/B.pak", 1},
+		{[]string{"C.pak"}, "/t/C.pak", 0},
+		{[]string{"C.pak"}, "/t/B.pak", 3}, // C, divider, A, B
+		{[]string{"A.pak", "B.pak", "C.pak"}, "/t/C.pak", 2},
+	}
+	for _, tt := range tests {
+		pinned, rest := splitPinnedTools(tools, tt.pinned)
+		if got := pickToolListIndex(pinned, rest, tt.path); got != tt.want {
+			t.Errorf("pickToolListIndex(%v, %s) = %d, want %d", tt.pinned, tt.path, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,8 @@ go 1.25.7
 require (
 	github.com/BrandonKowalski/certifiable v1.3.0
 	github.com/BrandonKowalski/gabagool/v2 v2.9.3
+	go.uber.org/atomic v1.11.0
+	golang.org/x/image v0.34.0
 )
 
 require (
@@ -12,8 +14,8 @@ require (
 	github.com/srwiley/oksvg v0.0.0-20221011165216-be6e8873101c // indirect
 	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef // indirect
 	github.com/veandco/go-sdl2 v0.4.40 // indirect
-	go.uber.org/atomic v1.11.0 // indirect
-	golang.org/x/image v0.34.0 // indirect
+	golang.org/x/crypto v0.46.0 // indirect
 	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/text v0.33.0 // indirect
 )
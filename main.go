@@ -1,8 +1,13 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -16,10 +21,21 @@ type Platform string
 
 const (
 	PlatformMac    Platform = "mac"
+	PlatformTG3040 Platform = "tg3040"
 	PlatformTG5040 Platform = "tg5040"
 	PlatformTG5050 Platform = "tg5050"
 )
 
+// dirName returns the platform directory name used on the SD card (Tools/<dir>, Emus/<dir>,
+// .userdata/<dir>). TG3040 shares the TG5040 filesystem layout, and the macOS mock card
+// mirrors it.
+func (p Platform) dirName() string {
+	if p == PlatformTG3040 || p == PlatformMac {
+		return string(PlatformTG5040)
+	}
+	return string(p)
+}
+
 var platform Platform
 
 // isBrick is true when running on the TrimUI Brick (1024×768).
@@ -27,24 +43,51 @@ var platform Platform
 // Smart Pro; both share PLATFORM="tg5040" and the same filesystem layout.
 var isBrick bool
 
+// sessionID is a random hex identifier generated at startup and included in the log
+// prefix, so lines from one run can be told apart when sessions are interleaved.
+var sessionID string
+
 func main() {
 	platform = PlatformTG5040
 	platformEnv := strings.ToUpper(os.Getenv("PLATFORM"))
 	if strings.Contains(platformEnv, "TG5050") {
 		platform = PlatformTG5050
-	} else if strings.Contains(platformEnv, "TG5040") || strings.Contains(platformEnv, "TG3040") {
+	} else if strings.Contains(platformEnv, "TG3040") {
+		platform = PlatformTG3040
+	} else if strings.Contains(platformEnv, "TG5040") {
 		platform = PlatformTG5040
 	}
+	platformSource := "env"
+	if platformEnv == "" {
+		// Started outside NextUI's launch.sh: ask the device tree.
+		platformSource = "default"
+		if p, ok := detectPlatformFromDTS(); ok {
+			platform, platformSource = p, "device-tree"
+		}
+	}
 
 	// DEVICE is set by NextUI's launch.sh to "brick" or "smartpro" for tg5040 devices.
 	// Both share the same PLATFORM="tg5040" filesystem layout; only screen dimensions differ.
 	isBrick = strings.EqualFold(os.Getenv("DEVICE"), "brick")
+	if os.Getenv("DEVICE") == "" && strings.Contains(strings.ToLower(readDeviceTreeModel()), "brick") {
+		isBrick = true
+	}
 
+	sessionID = newSessionID()
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
-	log.SetPrefix("shortcuts: ")
+	log.SetPrefix("shortcuts[" + sessionID + "]: ")
+
+	// Export the resolved card so every path helper, and anything we launch, agrees on it
+	// even when started from a script that sets neither SDCARD_PATH nor sdcard_path.
+	sdcard, sdcardSource := resolveSDCardRoot()
+	os.Setenv("SDCARD_PATH", sdcard)
 
 	logPath := getLogPath()
-	log.Printf("startup: platform=%s device=%s isBrick=%v logPath=%s", platform, os.Getenv("DEVICE"), isBrick, logPath)
+	if err := redirectLog(logPath); err != nil {
+		fmt.Fprintf(os.Stderr, "shortcuts: %v\n", err)
+	}
+	log.Printf("startup: platform=%s source=%s device=%s isBrick=%v logPath=%s", platform, platformSource, os.Getenv("DEVICE"), isBrick, logPath)
+	log.Printf("startup: sdcard=%s source=%s", sdcard, sdcardSource)
 	gaba.Init(gaba.Options{
 		WindowTitle:    "Shortcuts",
 		ShowBackground: true,
@@ -53,7 +96,58 @@ func main() {
 	})
 	defer gaba.Close()
 
-	ensureBridgeEmu()
+	// Devices with internal storage plus an external card: let the user pick which one,
+	// unless NextUI's launch.sh already exported the card to use.
+	if sdcardSource != "env" && platform != PlatformMac {
+		if cards := discoverSDCards(); len(cards) > 1 {
+			if card, ok := pickSDCard(cards); ok && card != sdcard {
+				os.Setenv("SDCARD_PATH", card)
+				logAction("sdcard_selected", "path", card)
+				logPath = getLogPath()
+				logError("moving log to the selected card", redirectLog(logPath))
+				log.Printf("startup: sdcard=%s source=picker logPath=%s", card, logPath)
+			}
+		}
+	}
+
+	logError("ensuring bridge emulator", ensureBridgeEmu())
+	if directBridgeInstalled() {
+		logError("ensuring direct bridge emulator", ensureDirectBridgeEmu())
+	}
+
+	// Check the configured sort prefixes, and offer to rename existing shortcuts if they
+	// changed since the last run.
+	sortPrefixStartupFlow()
+
+	// Scan consoles while the main menu is shown, so the first console picker opens quickly.
+	settings := loadSettings()
+	startConsolePrescan(settings)
+
+	// Launch stats and missing-target times only touch marker files, so they can update
+	// while the menu is shown. Marker updates go through updateShortcutMeta, which keeps
+	// them from overwriting changes the UI makes at the same time.
+	go func() {
+		if _, err := refreshAccessStats(settings); err != nil {
+			logError("refreshing access stats", err)
+		}
+		if _, err := findStaleShortcuts(staleShortcutDays, settings); err != nil {
+			logError("checking for stale shortcuts", err)
+		}
+	}()
+
+	// Other tools can launch us with a deep link to jump straight into a flow.
+	if len(os.Args) > 1 && strings.HasPrefix(os.Args[1], deepLinkScheme+"://") {
+		if action, params, ok := parseDeepLink(os.Args[1]); ok {
+			log.Printf("startup: deep link action=%s params=%v", action, params)
+			if runDeepLink(action, params) {
+				logError("saving artwork totals", flushArtTotals())
+				return
+			}
+		} else {
+			log.Printf("startup: ignoring malformed deep link %q", os.Args[1])
+		}
+	}
+
 	runApp()
 }
 
@@ -65,26 +159,113 @@ func runApp() {
 			addROMShortcutFlow()
 		case mainActionAddTool:
 			addToolShortcutFlow()
+		case mainActionAddAll:
+			addAllConsoleShortcutsFlow()
 		case mainActionManage:
 			manageShortcutsFlow()
 		case mainActionManageMedia:
 			manageMediaFlow()
+		case mainActionHealth:
+			healthCheckFlow()
 		case mainActionSettings:
 			showSettingsScreen()
 		case mainActionQuit:
 			return
 		}
+		// Artwork made by the flow counts towards the totals in Manage Artwork.
+		logError("saving artwork totals", flushArtTotals())
 	}
 }
 
 func getLogPath() string {
-	sdcard := os.Getenv("SDCARD_PATH")
-	if sdcard == "" {
-		sdcard = "/mnt/SDCARD"
+	logDir := filepath.Join(getSDCardRoot(), ".userdata", platform.dirName(), "logs")
+	return filepath.Join(logDir, "shortcuts.log")
+}
+
+// ensureLogDir creates the directory holding logPath, so gaba.Init can open the log file on
+// a fresh card.
+func ensureLogDir(logPath string) error {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("creating log dir: %w", err)
 	}
+	return nil
+}
 
-	logDir := filepath.Join(sdcard, ".userdata", string(platform), "logs")
-	return filepath.Join(logDir, "shortcuts.log")
+// logFile is the file redirectLog last pointed the log package at, nil before that.
+var logFile *os.File
+
+// redirectLog sends the log package's output to logPath as well as stderr, closing the
+// previous log file. main calls it at startup and again when the card picker chooses
+// another card, so the app's own lines follow that card. gabagool's slog logger is
+// separate: gaba.Init opens its LogPath once, so its lines stay on the startup card.
+func redirectLog(logPath string) error {
+	if err := ensureLogDir(logPath); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening log: %w", err)
+	}
+	log.SetOutput(io.MultiWriter(os.Stderr, f))
+	if logFile != nil {
+		logFile.Close()
+	}
+	logFile = f
+	return nil
+}
+
+// deepLinkScheme and deepLinkHost form the URL prefix accepted by parseDeepLink.
+const (
+	deepLinkScheme = "nextui"
+	deepLinkHost   = "shortcuts"
+)
+
+// Deep link actions.
+const (
+	deepLinkAddROM = "add-rom"
+)
+
+// parseDeepLink parses a deep link such as
+// "nextui://shortcuts/add-rom?console=Game%20Boy%20Advance%20%28GBA%29&rom=Fire%20Red.gba"
+// into its action ("add-rom") and decoded query parameters.
+func parseDeepLink(arg string) (action string, params map[string]string, ok bool) {
+	u, err := url.Parse(arg)
+	if err != nil || u.Scheme != deepLinkScheme || u.Host != deepLinkHost {
+		return "", nil, false
+	}
+	action = strings.Trim(u.Path, "/")
+	if action == "" {
+		return "", nil, false
+	}
+	params = make(map[string]string)
+	for k, v := range u.Query() {
+		if len(v) > 0 {
+			params[k] = v[0]
+		}
+	}
+	return action, params, true
+}
+
+// newSessionID returns 8 random bytes as hex. Falls back to the PID if crypto/rand fails.
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("pid%d", os.Getpid())
+	}
+	return hex.EncodeToString(b)
+}
+
+// logAction logs a confirmed user action at INFO level as "action=<name>" followed by
+// quoted key=value fields, so a session can be replayed from the log.
+// e.g. logAction("shortcut_created", "folder", folderName, "tag", tag)
+func logAction(action string, kv ...any) {
+	var sb strings.Builder
+	sb.WriteString("INFO action=")
+	sb.WriteString(action)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fmt.Fprintf(&sb, " %v=%q", kv[i], fmt.Sprint(kv[i+1]))
+	}
+	log.Print(sb.String())
 }
 
 // isErrCancelled checks if the error is a Gabagool user-cancelled error.
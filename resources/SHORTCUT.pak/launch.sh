@@ -1,7 +1,23 @@
 #!/bin/sh
-# SHORTCUT.pak — Bridge emulator for tool shortcuts.
-# Receives path to a marker file, reads the real tool path, execs it.
+# SHORTCUT.pak - Bridge emulator for tool and save state shortcuts.
+# version: 4
 TARGET=$(cat "$1")
+SHORTCUT_DIR=$(dirname "$1")
+if [ -f "$SHORTCUT_DIR/env.sh" ]; then
+    . "$SHORTCUT_DIR/env.sh"
+fi
+set --
+if [ -f "$SHORTCUT_DIR/rom" ]; then
+    ROM=$(cat "$SHORTCUT_DIR/rom")
+    case "$ROM" in
+    /*) ;;
+    *) ROM="$SHORTCUT_DIR/$ROM" ;;
+    esac
+    set -- "$ROM"
+fi
+if [ -f "$SHORTCUT_DIR/savestate" ]; then
+    set -- "$@" "$SHORTCUT_DIR/savestate"
+fi
 if [ -x "$TARGET/launch.sh" ]; then
-    exec "$TARGET/launch.sh"
+    exec "$TARGET/launch.sh" "$@"
 fi
@@ -1,12 +1,23 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"go.uber.org/atomic"
 )
 
 // ── Main menu ────────────────────────────────────────────────
@@ -17,17 +28,25 @@ const (
 	mainActionQuit mainAction = iota
 	mainActionAddROM
 	mainActionAddTool
+	mainActionAddAll
 	mainActionManage
 	mainActionManageMedia
+	mainActionHealth
 	mainActionSettings
 )
 
 func showMainMenu() mainAction {
+	addToolLabel := "Add Tool Shortcut"
+	if !verifyBridgeEmu() {
+		addToolLabel += "  [unavailable]"
+	}
 	items := []gaba.MenuItem{
 		{Text: "Add ROM Shortcut"},
-		{Text: "Add Tool Shortcut"},
+		{Text: addToolLabel},
+		{Text: "Add All ROMs from Console"},
 		{Text: "Manage Shortcuts"},
 		{Text: "Manage Artwork"},
+		{Text: "Health Check"},
 		{Text: "Settings"},
 	}
 
@@ -58,12 +77,18 @@ func showMainMenu() mainAction {
 		log.Printf("ui: main menu -> add tool shortcut")
 		return mainActionAddTool
 	case 2:
+		log.Printf("ui: main menu -> add all roms from console")
+		return mainActionAddAll
+	case 3:
 		log.Printf("ui: main menu -> manage shortcuts")
 		return mainActionManage
-	case 3:
+	case 4:
 		log.Printf("ui: main menu -> manage artwork")
 		return mainActionManageMedia
-	case 4:
+	case 5:
+		log.Printf("ui: main menu -> health check")
+		return mainActionHealth
+	case 6:
 		log.Printf("ui: main menu -> settings")
 		return mainActionSettings
 	default:
@@ -71,15 +96,45 @@ func showMainMenu() mainAction {
 	}
 }
 
+// ── SD card picker ───────────────────────────────────────────
+
+// pickSDCard asks which mounted card to operate on. Returns false if the user backs out,
+// in which case the default card is used.
+func pickSDCard(cards []string) (string, bool) {
+	items := make([]gaba.MenuItem, len(cards))
+	for i, c := range cards {
+		items[i] = gaba.MenuItem{Text: c}
+	}
+	opts := gaba.DefaultListOptions("Select SD Card", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Default"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 {
+		return "", false
+	}
+	return cards[result.Selected[0]], true
+}
+
 // ── Position picker ──────────────────────────────────────────
 
 // pickPosition presents a list for choosing where the shortcut will sort in the menu.
-func pickPosition() (ShortcutPosition, bool) {
+// When displayName is non-empty, each option previews the folder name it produces, with
+// invisible sort prefixes spelled out.
+func pickPosition(displayName, tag string, prefixes sortPrefixes) (ShortcutPosition, bool) {
 	items := []gaba.MenuItem{
 		{Text: "Alphabetical"},
 		{Text: "Top         (before A)"},
 		{Text: "Bottom  (after Z)"},
 	}
+	if displayName != "" {
+		positions := []ShortcutPosition{ShortcutPositionAlpha, ShortcutPositionTop, ShortcutPositionBottom}
+		labels := []string{"Alphabetical", "Top", "Bottom"}
+		for i, pos := range positions {
+			items[i].Text = fmt.Sprintf("%s  →  %s", labels[i], visibleFolderName(buildFolderName(pos, displayName, tag, prefixes)))
+		}
+	}
 	opts := gaba.DefaultListOptions("Shortcut Position", items)
 	opts.FooterHelpItems = []gaba.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Back"},
@@ -92,7 +147,7 @@ func pickPosition() (ShortcutPosition, bool) {
 	if err != nil || len(result.Selected) == 0 {
 		return ShortcutPositionBottom, false
 	}
-	log.Printf("ui: position picked: %d", result.Selected[0])
+	logAction("position_picked", "index", result.Selected[0])
 	switch result.Selected[0] {
 	case 1:
 		return ShortcutPositionTop, true
@@ -103,13 +158,82 @@ func pickPosition() (ShortcutPosition, bool) {
 	}
 }
 
+// offerTrashRestore looks for a matching shortcut in Roms/.trash/ and, if there is one,
+// asks whether to restore it or create a new one. proceed is false when the caller should
+// stop: the trashed shortcut was restored, or B was pressed. When a new one is to be
+// created, discard is the trashed folder; the caller passes it to discardReplacedShortcut
+// once the new shortcut exists, so backing out later in the flow loses nothing.
+func offerTrashRestore(displayName, tag string, prefixes sortPrefixes) (discard string, proceed bool) {
+	trashed := findTrashedShortcut(displayName, tag, prefixes)
+	if trashed == "" {
+		return "", true
+	}
+	items := []gaba.MenuItem{
+		{Text: "Restore deleted shortcut"},
+		{Text: "Create new (deletes the old one)"},
+	}
+	opts := gaba.DefaultListOptions("Deleted Shortcut Found", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Cancel"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	result, err := gaba.List(opts)
+	if err != nil || result == nil || len(result.Selected) == 0 {
+		return "", false
+	}
+	if result.Selected[0] == 1 {
+		return trashed, true
+	}
+	if err := restoreTrashedShortcut(trashed); err != nil {
+		logError("restoring shortcut", err)
+		showError("Could not restore the shortcut.")
+		return "", false
+	}
+	logError("updating shortcut registry", updateShortcutRegistry(loadSettings()))
+	logAction("shortcut_restored", "folder", filepath.Base(trashed))
+	confirmationMessage(
+		fmt.Sprintf("Shortcut restored!\n\n%s\n\nwill appear on your main menu.", filepath.Base(trashed)),
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
+	return "", false
+}
+
+// discardReplacedShortcut permanently deletes the trashed shortcut that a newly created
+// one replaces (see offerTrashRestore). It does nothing when discard is "".
+func discardReplacedShortcut(discard string) {
+	if discard == "" {
+		return
+	}
+	logError("discarding trashed shortcut", discardTrashedShortcut(discard))
+	logAction("trashed_shortcut_discarded", "folder", filepath.Base(discard))
+}
+
+// visibleFolderName spells out invisible characters such as the default Bottom prefix
+// as `\uFEFF` for display.
+func visibleFolderName(folderName string) string {
+	var sb strings.Builder
+	for _, r := range folderName {
+		if unicode.IsGraphic(r) {
+			sb.WriteRune(r)
+		} else {
+			fmt.Fprintf(&sb, `\u%04X`, r)
+		}
+	}
+	return sb.String()
+}
+
 // ── Add ROM Shortcut flow ────────────────────────────────────
 
 func addROMShortcutFlow() {
-	// Step 1: Pick a console
-	console, ok := pickConsole()
+	// Step 1: Pick a console, unless one is pinned
+	console, ok := pinnedConsole()
 	if !ok {
-		return
+		if console, ok = pickConsole(); !ok {
+			return
+		}
+		offerPinConsole(console)
 	}
 
 	// Step 2: Pick a ROM from that console
@@ -118,28 +242,94 @@ func addROMShortcutFlow() {
 		return
 	}
 
-	displayName := rom.Display
+	confirmROMShortcutFlow(console, rom)
+}
+
+// confirmROMShortcutFlow runs the remaining Add ROM Shortcut steps (duplicate check,
+// position, confirmation, creation) for an already chosen console and ROM.
+// Used directly by deep links that pre-select both.
+func confirmROMShortcutFlow(console ConsoleDir, rom ROMFile) {
+	settings := loadSettings()
 	log.Printf("ui: add rom shortcut: console=%s rom=%s multiDisc=%v", console.Display, rom.Name, rom.IsMultiDisc)
 
-	// Step 3: Check if shortcut already exists
-	if shortcutExists(displayName, console.Tag) {
-		gaba.ConfirmationMessage(
-			fmt.Sprintf("A shortcut for \"%s\" already exists.", displayName),
+	// Disabled ROMs (and ROMs in disabled consoles) are only listed with Show hidden on,
+	// and NextUI won't launch them.
+	if rom.IsDisabled || console.IsDisabled {
+		what := "ROM"
+		if !rom.IsDisabled {
+			what = "console"
+		}
+		log.Printf("ui: add rom shortcut: %s is disabled", what)
+		result, err := confirmationMessage(
+			fmt.Sprintf("This %s is currently disabled and\nmay not launch correctly.", what),
 			[]gaba.FooterHelpItem{
-				{ButtonName: "B", HelpText: "Back"},
+				{HelpText: "Cancel"},
+				{HelpText: "Continue", IsConfirmButton: true},
+			},
+		)
+		if isErrCancelled(err) || result == nil || !result.Confirmed {
+			return
+		}
+	}
+
+	// Step 3: Name the shortcut, starting from the display name template
+	displayName, ok := pickShortcutName(applyDisplayNameTemplate(settings.DisplayNameTemplate, rom, console))
+	if !ok {
+		return
+	}
+
+	// Check if shortcut already exists. A shortcut with the same name for another ROM (e.g.
+	// another region) is a collision; the user can add a suffix and try again.
+	prefixes := settings.sortPrefixes()
+	for {
+		target, found := existingShortcutTarget(displayName, console.Tag, prefixes)
+		if !found {
+			break
+		}
+		if target == "" || filepath.Clean(target) == filepath.Clean(romTargetPath(rom)) {
+			confirmationMessage(
+				fmt.Sprintf("A shortcut for \"%s\" already exists.", displayName),
+				[]gaba.FooterHelpItem{
+					{HelpText: "Back"},
+				},
+			)
+			return
+		}
+		log.Printf("ui: add rom shortcut: name %q is taken by a shortcut to %s", displayName, target)
+		result, err := confirmationMessage(
+			fmt.Sprintf("\"%s\" is already used by a shortcut to\n\n%s\n\nAdd a suffix, such as the region,\nto tell them apart.", displayName, filepath.Base(target)),
+			[]gaba.FooterHelpItem{
+				{HelpText: "Cancel"},
+				{HelpText: "Edit name", IsConfirmButton: true},
 			},
-			gaba.MessageOptions{},
 		)
+		if isErrCancelled(err) || result == nil || !result.Confirmed {
+			return
+		}
+		edited, ok := textInput(disambiguatedName(displayName, rom), "Shortcut name", maxDisplayNameLen)
+		if !ok {
+			return
+		}
+		displayName = edited
+	}
+	discard, ok := offerTrashRestore(displayName, console.Tag, prefixes)
+	if !ok {
 		return
 	}
 
 	// Step 4: Pick position
-	pos, ok := pickPosition()
+	pos, ok := pickPosition(displayName, console.Tag, prefixes)
 	if !ok {
 		return
 	}
 
-	folderName := buildFolderName(pos, displayName, console.Tag)
+	romsDir, _, _ := getBasePaths()
+	baseFolderName := buildFolderName(pos, displayName, console.Tag, prefixes)
+	folderName, err := resolveNameConflict(romsDir, baseFolderName)
+	if err != nil {
+		showOperationError("creating rom shortcut", "create the shortcut", err)
+		return
+	}
 
 	// Step 5: Confirm creation
 	romDesc := rom.Name
@@ -151,14 +341,17 @@ func addROMShortcutFlow() {
 	}
 	msg := fmt.Sprintf("Create shortcut?\n\n%s\n\nConsole: %s\nROM: %s",
 		folderName, console.Display, romDesc)
+	if rom.CoreOverride != "" {
+		msg += "\nCore: " + rom.CoreOverride
+	}
+	if folderName != baseFolderName {
+		msg += "\n\nA folder with that name already exists,\nso a number was added."
+	}
 
-	result, err := gaba.ConfirmationMessage(msg,
+	result, err := confirmationMessage(msg,
 		[]gaba.FooterHelpItem{
-			{ButtonName: "B", HelpText: "Cancel"},
-			{ButtonName: "A", HelpText: "Create", IsConfirmButton: true},
-		},
-		gaba.MessageOptions{
-			ConfirmButton: constants.VirtualButtonA,
+			{HelpText: "Cancel"},
+			{HelpText: "Create", IsConfirmButton: true},
 		},
 	)
 	if isErrCancelled(err) || result == nil || !result.Confirmed {
@@ -166,519 +359,3024 @@ func addROMShortcutFlow() {
 	}
 
 	// Step 6: Create the shortcut
-	settings := loadSettings()
-	gaba.ProcessMessage("Creating shortcut...",
+	var artErr error
+	_, err = gaba.ProcessMessage("Creating shortcut...",
 		gaba.ProcessMessageOptions{ShowThemeBackground: true},
 		func() (any, error) {
-			return nil, createROMShortcut(displayName, console.Tag, console.Name, rom, pos, settings)
+			var err error
+			artErr, err = createROMShortcut(displayName, console.Tag, console.Name, rom, pos, loadCoreMap(), settings)
+			logError("updating shortcut registry", updateShortcutRegistry(settings))
+			return nil, err
 		},
 	)
+	if err != nil {
+		showOperationError("creating rom shortcut", "create the shortcut", err)
+		return
+	}
+	logAction("shortcut_created", "type", "rom", "folder", folderName, "console", console.Name, "rom", rom.Path)
+	discardReplacedShortcut(discard)
 
-	gaba.ConfirmationMessage(
-		fmt.Sprintf("Shortcut created!\n\n%s\n\nwill appear on your main menu.", folderName),
+	confirmationMessage(
+		fmt.Sprintf("Shortcut created!\n\n%s\n\nwill appear on your main menu.%s", folderName, artworkNote(artErr)),
 		[]gaba.FooterHelpItem{
-			{ButtonName: "A", HelpText: "OK", IsConfirmButton: true},
-		},
-		gaba.MessageOptions{
-			ConfirmButton: constants.VirtualButtonA,
+			{HelpText: "OK", IsConfirmButton: true},
 		},
 	)
 }
 
-func pickConsole() (ConsoleDir, bool) {
-	settings := loadSettings()
-	consoles, err := scanConsoleDirs(settings.ShowHidden)
+// saveStateShortcutFlow creates a shortcut that boots rom and loads one of its save
+// states, chosen from the states NextUI has written for it. pickROM only offers it when
+// saveStateEmuPak finds a pak for console that loads the state.
+func saveStateShortcutFlow(console ConsoleDir, rom ROMFile) {
+	states, err := scanSaveStates(console.Tag, rom)
 	if err != nil {
-		logError("scanning consoles", err)
-		showError("Could not read ROM folders.")
-		return ConsoleDir{}, false
+		logError("scanning save states", err)
+		showError("Could not read save states.")
+		return
 	}
-	if len(consoles) == 0 {
-		showError("No ROM folders found.")
-		return ConsoleDir{}, false
+	if len(states) == 0 {
+		showError(fmt.Sprintf("No save states found for %s.", rom.Display))
+		return
 	}
 
-	items := make([]gaba.MenuItem, len(consoles))
-	for i, c := range consoles {
-		text := c.Display
-		if c.IsDisabled {
-			text += "  [disabled]"
-		}
-		items[i] = gaba.MenuItem{Text: text}
+	items := make([]gaba.MenuItem, len(states))
+	for i, st := range states {
+		items[i] = gaba.MenuItem{Text: fmt.Sprintf("Slot %d  (%s)", st.Slot, st.ModTime.Local().Format("2006-01-02 15:04"))}
 	}
-
-	opts := gaba.DefaultListOptions("Select Console", items)
+	opts := gaba.DefaultListOptions("Save State", items)
 	opts.FooterHelpItems = []gaba.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Back"},
 		{ButtonName: "A", HelpText: "Select"},
 	}
-
 	result, err := gaba.List(opts)
-	if isErrCancelled(err) {
-		return ConsoleDir{}, false
-	}
-	if err != nil || len(result.Selected) == 0 {
-		return ConsoleDir{}, false
+	if isErrCancelled(err) || err != nil || len(result.Selected) == 0 {
+		return
 	}
+	state := states[result.Selected[0]]
+	log.Printf("ui: add save state shortcut: rom=%s state=%s", rom.Name, state.Path)
 
-	log.Printf("ui: selected console index=%d name=%s", result.Selected[0], consoles[result.Selected[0]].Display)
-	return consoles[result.Selected[0]], true
-}
-
-func pickROM(console ConsoleDir) (ROMFile, bool) {
 	settings := loadSettings()
-	roms, err := scanROMs(console.Path, settings.ShowHidden)
-	if err != nil {
-		logError("scanning ROMs", err)
-		showError("Could not read ROMs.")
-		return ROMFile{}, false
-	}
-	if len(roms) == 0 {
-		showError(fmt.Sprintf("No ROMs found in %s.", console.Display))
-		return ROMFile{}, false
-	}
-
-	items := make([]gaba.MenuItem, len(roms))
-	for i, r := range roms {
-		text := r.Display
-		if romDir := filepath.Dir(r.Path); romDir != console.Path {
-			subDir, _ := filepath.Rel(console.Path, romDir)
-			text = subDir + " / " + text
-		}
-		switch {
-		case r.IsMultiDisc:
-			text += "  [Multi]"
-		case r.IsCueFolder:
-			text += "  [CUE]"
-		}
-		if r.IsDisabled {
-			text += "  [disabled]"
-		}
-		items[i] = gaba.MenuItem{Text: text}
-	}
-
-	opts := gaba.DefaultListOptions(console.Display, items)
-	opts.FooterHelpItems = []gaba.FooterHelpItem{
-		{ButtonName: "B", HelpText: "Back"},
-		{ButtonName: "A", HelpText: "Select"},
-	}
-
-	result, err := gaba.List(opts)
-	if isErrCancelled(err) {
-		return ROMFile{}, false
-	}
-	if err != nil || len(result.Selected) == 0 {
-		return ROMFile{}, false
-	}
-
-	log.Printf("ui: selected rom index=%d name=%s", result.Selected[0], roms[result.Selected[0]].Name)
-	return roms[result.Selected[0]], true
-}
-
-// ── Add Tool Shortcut flow ───────────────────────────────────
-
-func addToolShortcutFlow() {
-	tool, ok := pickTool()
+	displayName, ok := pickShortcutName(fmt.Sprintf("%s - Slot %d", rom.Display, state.Slot))
 	if !ok {
 		return
 	}
-
-	displayName := tool.Display
-	log.Printf("ui: add tool shortcut: tool=%s", tool.Name)
-
-	// Check if shortcut already exists
-	if shortcutExists(displayName, bridgeEmuTag) {
-		gaba.ConfirmationMessage(
-			fmt.Sprintf("A shortcut for \"%s\" already exists.", displayName),
-			[]gaba.FooterHelpItem{
-				{ButtonName: "B", HelpText: "Back"},
-			},
-			gaba.MessageOptions{},
-		)
+	prefixes := settings.sortPrefixes()
+	if _, found := existingShortcutTarget(displayName, bridgeEmuTag, prefixes); found {
+		showError(fmt.Sprintf("A shortcut named \"%s\" already exists.", displayName))
 		return
 	}
-
-	// Pick position
-	pos, ok := pickPosition()
+	pos, ok := pickPosition(displayName, bridgeEmuTag, prefixes)
 	if !ok {
 		return
 	}
 
-	folderName := buildFolderName(pos, displayName, bridgeEmuTag)
-
-	// Confirm creation
-	msg := fmt.Sprintf("Create shortcut?\n\n%s\n\nTool: %s",
-		folderName, tool.Name)
-
-	result, err := gaba.ConfirmationMessage(msg,
+	romsDir, _, _ := getBasePaths()
+	folderName, err := resolveNameConflict(romsDir, buildFolderName(pos, displayName, bridgeEmuTag, prefixes))
+	if err != nil {
+		showOperationError("creating save state shortcut", "create the shortcut", err)
+		return
+	}
+	confirmed, err := confirmationMessage(
+		fmt.Sprintf("Create shortcut?\n\n%s\n\nROM: %s\nState: slot %d", folderName, rom.Name, state.Slot),
 		[]gaba.FooterHelpItem{
-			{ButtonName: "B", HelpText: "Cancel"},
-			{ButtonName: "A", HelpText: "Create", IsConfirmButton: true},
-		},
-		gaba.MessageOptions{
-			ConfirmButton: constants.VirtualButtonA,
+			{HelpText: "Cancel"},
+			{HelpText: "Create", IsConfirmButton: true},
 		},
 	)
-	if isErrCancelled(err) || result == nil || !result.Confirmed {
+	if isErrCancelled(err) || confirmed == nil || !confirmed.Confirmed {
+		return
+	}
+	if !requireBridgeEmu() {
 		return
 	}
 
-	// Create shortcut
-	settings := loadSettings()
-	gaba.ProcessMessage("Creating shortcut...",
+	var artErr error
+	_, err = gaba.ProcessMessage("Creating shortcut...",
 		gaba.ProcessMessageOptions{ShowThemeBackground: true},
 		func() (any, error) {
-			return nil, createToolShortcut(displayName, tool.Path, pos, settings)
+			var err error
+			artErr, err = createSaveStateShortcut(displayName, console.Tag, console.Name, rom, state.Path, pos, settings)
+			logError("updating shortcut registry", updateShortcutRegistry(settings))
+			return nil, err
 		},
 	)
+	if err != nil {
+		showOperationError("creating save state shortcut", "create the shortcut", err)
+		return
+	}
+	logAction("shortcut_created", "type", "savestate", "folder", folderName, "console", console.Name, "rom", rom.Path, "slot", state.Slot)
 
-	gaba.ConfirmationMessage(
-		fmt.Sprintf("Shortcut created!\n\n%s\n\nwill appear on your main menu.", folderName),
+	confirmationMessage(
+		fmt.Sprintf("Shortcut created!\n\n%s\n\nwill appear on your main menu.%s", folderName, artworkNote(artErr)),
 		[]gaba.FooterHelpItem{
-			{ButtonName: "A", HelpText: "OK", IsConfirmButton: true},
-		},
-		gaba.MessageOptions{
-			ConfirmButton: constants.VirtualButtonA,
+			{HelpText: "OK", IsConfirmButton: true},
 		},
 	)
 }
 
-func pickTool() (ToolPak, bool) {
-	settings := loadSettings()
-	tools, err := scanTools(settings.ShowHidden)
-	if err != nil {
-		logError("scanning tools", err)
-		showError("Could not read Tools folder.")
-		return ToolPak{}, false
-	}
-	if len(tools) == 0 {
-		showError("No tools found.")
-		return ToolPak{}, false
-	}
+// pickShortcutName shows the proposed display name. X edits it with the on-screen keyboard,
+// A accepts it. Returns false if the user backs out.
+func pickShortcutName(name string) (string, bool) {
+	for {
+		opts := gaba.DefaultListOptions("Shortcut Name", []gaba.MenuItem{{Text: name}})
+		opts.ActionButton = constants.VirtualButtonX
+		opts.FooterHelpItems = []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "Back"},
+			{ButtonName: "X", HelpText: "Edit"},
+			{ButtonName: "A", HelpText: "Continue"},
+		}
+		result, err := gaba.List(opts)
+		if err != nil || len(result.Selected) == 0 {
+			return "", false
+		}
+		if result.Action != gaba.ListActionTriggered {
+			return name, true
+		}
 
-	items := make([]gaba.MenuItem, len(tools))
-	for i, t := range tools {
-		items[i] = gaba.MenuItem{Text: t.Display}
+		if edited, ok := textInput(name, "Shortcut name", maxDisplayNameLen); ok {
+			log.Printf("ui: shortcut name edited: %q -> %q", name, edited)
+			name = edited
+		}
 	}
+}
 
-	opts := gaba.DefaultListOptions("Select Tool", items)
-	opts.FooterHelpItems = []gaba.FooterHelpItem{
-		{ButtonName: "B", HelpText: "Back"},
-		{ButtonName: "A", HelpText: "Select"},
-	}
+// maxDisplayNameLen caps edited shortcut names, in characters. FAT32 allows 255 per file
+// name, and the .m3u name adds the sort prefix, tag and extension to the display name.
+const maxDisplayNameLen = 200
 
-	result, err := gaba.List(opts)
-	if isErrCancelled(err) {
-		return ToolPak{}, false
+// textInput edits initial with the on-screen keyboard and returns the trimmed result,
+// cut to maxLen characters. Returns false if the user cancels or clears the text.
+func textInput(initial, hint string, maxLen int) (string, bool) {
+	kb, err := gaba.Keyboard(initial, hint)
+	if err != nil || kb == nil {
+		return "", false
 	}
-	if err != nil || len(result.Selected) == 0 {
-		return ToolPak{}, false
+	text := strings.TrimSpace(kb.Text)
+	if runes := []rune(text); len(runes) > maxLen {
+		log.Printf("ui: text input: cut from %d to %d characters", len(runes), maxLen)
+		text = strings.TrimSpace(string(runes[:maxLen]))
 	}
-
-	log.Printf("ui: selected tool index=%d name=%s", result.Selected[0], tools[result.Selected[0]].Name)
-	return tools[result.Selected[0]], true
+	return text, text != ""
 }
 
-// ── Manage existing shortcuts ────────────────────────────────
+func pickConsole() (ConsoleDir, bool) {
+	settings := loadSettings()
+	consoles, err := pickerConsoleDirs(settings)
+	if err != nil {
+		logError("scanning consoles", err)
+		showError("Could not read ROM folders.")
+		return ConsoleDir{}, false
+	}
+	if len(consoles) == 0 {
+		showError("No ROM folders found.")
+		return ConsoleDir{}, false
+	}
+	sortConsolesByOrder(consoles, settings.ConsoleOrder)
 
-func manageShortcutsFlow() {
+	selected := 0
 	for {
-		shortcuts, err := scanShortcuts()
-		if err != nil {
-			logError("scanning shortcuts", err)
-			showError("Could not read shortcuts.")
-			return
-		}
-		if len(shortcuts) == 0 {
-			showError("No shortcuts found.\n\nCreate one first!")
-			return
-		}
-
-		items := make([]gaba.MenuItem, len(shortcuts))
-		for i, sc := range shortcuts {
-			kind := "ROM"
-			if sc.IsTool {
-				kind = "Tool"
+		counts := pickerROMCounts(consoles, settings)
+		items := make([]gaba.MenuItem, len(consoles))
+		for i, c := range consoles {
+			text := c.Display
+			if n, ok := counts[c.Name]; ok {
+				text += fmt.Sprintf("  (%d)", n)
+			}
+			if c.IsDisabled {
+				text += "  [disabled]"
 			}
-			items[i] = gaba.MenuItem{Text: fmt.Sprintf("%s  [%s]", sc.Display, kind)}
+			switch {
+			case c.IsEmpty:
+				text += "  [empty]"
+			case c.HiddenOnly:
+				text += "  [hidden content only]"
+			}
+			items[i] = gaba.MenuItem{Text: text}
 		}
 
-		opts := gaba.DefaultListOptions("Manage Shortcuts", items)
+		opts := gaba.DefaultListOptions("Select Console", items)
+		opts.SelectedIndex = max(min(selected, len(items)-1), 0)
+		opts.EmptyMessage = "No ROM folders found."
+		opts.ActionButton = constants.VirtualButtonX
+		opts.SecondaryActionButton = constants.VirtualButtonY
+		opts.TertiaryActionButton = constants.VirtualButtonSelect
 		opts.FooterHelpItems = []gaba.FooterHelpItem{
 			{ButtonName: "B", HelpText: "Back"},
-			{ButtonName: "A", HelpText: "Details"},
+			{ButtonName: "X", HelpText: "Emulators"},
+			{ButtonName: "Y", HelpText: "Enable/Disable"},
+			{ButtonName: "Select", HelpText: "Refresh counts"},
+			{ButtonName: "A", HelpText: "Select"},
 		}
 
 		result, err := gaba.List(opts)
 		if isErrCancelled(err) {
-			return
+			return ConsoleDir{}, false
 		}
 		if err != nil || len(result.Selected) == 0 {
-			return
+			return ConsoleDir{}, false
 		}
 
-		idx := result.Selected[0]
-		log.Printf("ui: manage shortcuts -> selected index=%d name=%s", idx, shortcuts[idx].Display)
-		action := showShortcutDetail(shortcuts[idx])
-		if action == detailActionDeleted || action == detailActionBack {
-			// Refresh the list (deleted or went back from detail)
+		// Select counts again right away.
+		if result.Action == gaba.ListActionTertiaryTriggered {
+			gaba.ProcessMessage("Counting ROMs...",
+				gaba.ProcessMessageOptions{ShowThemeBackground: true},
+				func() (any, error) {
+					storeROMCounts(consoleROMCounts(consoles, settings), time.Now())
+					return nil, nil
+				},
+			)
+			logAction("rom_counts_refreshed", "consoles", len(consoles))
+			continue
+		}
+
+		selected = result.Selected[0]
+		// X shows the emulators installed for the focused console, then returns here.
+		if result.Action == gaba.ListActionTriggered {
+			showConsoleEmulators(consoles[selected])
 			continue
 		}
+		// Y renames the folder to add or remove .disabled, then rescans.
+		if result.Action == gaba.ListActionSecondaryTriggered {
+			if toggleConsoleDisabled(consoles[selected], settings.ShowHidden) {
+				refreshed, err := scanConsoleDirs(settings.ShowHidden, settings.sortPrefixes())
+				if err != nil {
+					logError("scanning consoles", err)
+					showError("Could not read ROM folders.")
+					return ConsoleDir{}, false
+				}
+				consoles = refreshed
+				sortConsolesByOrder(consoles, settings.ConsoleOrder)
+			}
+			continue
+		}
+
+		logAction("console_selected", "index", selected, "console", consoles[selected].Name)
+		return consoles[selected], true
 	}
 }
 
-type detailAction int
+// toggleConsoleDisabled asks to disable or enable c and renames its folder. Disabling warns
+// about shortcuts into the console, which won't launch while it is disabled, and that it
+// will only be listed again with Show hidden on. Returns true if the folder was renamed.
+func toggleConsoleDisabled(c ConsoleDir, showHidden bool) bool {
+	verb := "Disable"
+	msg := fmt.Sprintf("Disable %s?\n\nNextUI will hide its ROMs until\nit is enabled again.", c.Display)
+	if c.IsDisabled {
+		verb = "Enable"
+		msg = fmt.Sprintf("Enable %s?", c.Display)
+	} else {
+		if n := countShortcutsIntoConsoleDir(c.Name, loadSettings()); n > 0 {
+			msg += fmt.Sprintf("\n\n%d shortcut(s) to its ROMs won't\nlaunch while it is disabled.", n)
+		}
+		if !showHidden {
+			msg += "\n\nTurn on Show hidden in Settings\nto list it here again."
+		}
+	}
+	result, err := confirmationMessage(msg,
+		[]gaba.FooterHelpItem{
+			{HelpText: "Cancel"},
+			{HelpText: verb, IsConfirmButton: true},
+		},
+	)
+	if isErrCancelled(err) || result == nil || !result.Confirmed {
+		return false
+	}
 
-const (
-	detailActionBack detailAction = iota
-	detailActionDeleted
-)
+	updated, err := setConsoleDisabled(c, !c.IsDisabled)
+	if err != nil {
+		showOperationError("toggling console", strings.ToLower(verb)+" the console", err)
+		return false
+	}
+	logAction("console_toggled", "from", c.Name, "to", updated.Name, "disabled", updated.IsDisabled)
+	return true
+}
+
+// pickerROMCounts returns the ROM counts the console picker shows. Stale or incomplete
+// counts are shown as they are while they are counted again in the background; consoles
+// without a count yet are listed without one.
+func pickerROMCounts(consoles []ConsoleDir, settings AppSettings) map[string]int {
+	counts, fresh := cachedROMCounts(consoles)
+	if !fresh {
+		startROMCountRefresh(consoles, settings)
+		// Picks up the last run's ROMCountCache on the first open.
+		counts, _ = cachedROMCounts(consoles)
+	}
+	return counts
+}
+
+// pickerConsoleDirs returns the consoles for pickConsole, from the startup prescan when it
+// is usable. If the prescan is still running, a spinner is shown until it finishes.
+func pickerConsoleDirs(settings AppSettings) ([]ConsoleDir, error) {
+	if pending := consolePrescanPending(); pending != nil {
+		gaba.ProcessMessage("Scanning consoles...",
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (any, error) {
+				<-pending
+				return nil, nil
+			},
+		)
+	}
+	if consoles, ok := takePrescannedConsoles(settings.ShowHidden); ok {
+		return consoles, nil
+	}
+	return scanConsoleDirs(settings.ShowHidden, settings.sortPrefixes())
+}
+
+// showConsoleEmulators lists the emulator paks installed for console's tag, so the user can
+// tell whether its shortcuts will launch before creating one.
+func showConsoleEmulators(console ConsoleDir) {
+	paks, err := scanEmulatorsForTag(console.Tag)
+	if err != nil {
+		logError("scanning emulators", err)
+		showError("Could not read the Emus folder.")
+		return
+	}
+
+	metadata := make([]gaba.MetadataItem, 0, len(paks))
+	for _, p := range paks {
+		kind := "Installed"
+		if p.IsSystem {
+			kind = "Built-in"
+		}
+		metadata = append(metadata, gaba.MetadataItem{Label: kind, Value: p.Name + ".pak"})
+	}
+	if len(paks) == 0 {
+		metadata = append(metadata, gaba.MetadataItem{
+			Label: "None", Value: fmt.Sprintf("no %s.pak found; shortcuts will not launch", console.Tag),
+		})
+	}
+
+	detailOpts := gaba.DefaultInfoScreenOptions()
+	detailOpts.Sections = []gaba.Section{
+		gaba.NewInfoSection(fmt.Sprintf("Emulators for %s", console.Tag), metadata),
+	}
+	detailOpts.ShowThemeBackground = true
+	detailOpts.ShowScrollbar = false
+	gaba.DetailScreen(console.Display, detailOpts, []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+	})
+}
+
+// pinnedConsole returns the console pinned in settings. It reports false when none is
+// pinned or the pinned folder no longer exists, so the caller falls back to pickConsole.
+func pinnedConsole() (ConsoleDir, bool) {
+	settings := loadSettings()
+	if settings.PinnedConsole == "" {
+		return ConsoleDir{}, false
+	}
+	console, err := findConsoleDir(settings.PinnedConsole, settings.ShowHidden, settings.sortPrefixes())
+	if err != nil {
+		log.Printf("ui: pinned console: %v — showing the picker", err)
+		return ConsoleDir{}, false
+	}
+	return console, true
+}
+
+// pinConsoleStreak is how many times in a row the same console must be picked before
+// offerPinConsole suggests pinning it.
+const pinConsoleStreak = 3
+
+// offerPinConsole counts consecutive picks of the same console and, the first time the
+// streak reaches pinConsoleStreak, offers to pin it. The offer is not repeated once
+// answered either way.
+func offerPinConsole(console ConsoleDir) {
+	settings := loadSettings()
+	if settings.PinConsoleOffered {
+		return
+	}
+	if settings.LastConsole == console.Name {
+		settings.ConsoleStreak++
+	} else {
+		settings.LastConsole, settings.ConsoleStreak = console.Name, 1
+	}
+	if settings.ConsoleStreak >= pinConsoleStreak {
+		settings.PinConsoleOffered = true
+		result, err := confirmationMessage(
+			fmt.Sprintf("Always use %s?\n\nAdd ROM Shortcut will skip the\nconsole list. You can unpin it\nin Settings.", console.Display),
+			[]gaba.FooterHelpItem{
+				{HelpText: "No"},
+				{HelpText: "Pin", IsConfirmButton: true},
+			},
+		)
+		if err == nil && result != nil && result.Confirmed {
+			settings.PinnedConsole = console.Name
+			logAction("console_pinned", "console", console.Name)
+		}
+	}
+	logError("saving console streak", saveSettings(settings))
+}
+
+func pickROM(console ConsoleDir) (ROMFile, bool) {
+	settings := loadSettings()
+	roms, err := scanROMs(console.Path, settings.ShowHidden, settings.ScanDepth)
+	if err != nil {
+		logError("scanning ROMs", err)
+		showError("Could not read ROMs.")
+		return ROMFile{}, false
+	}
+	if len(roms) == 0 {
+		showError(fmt.Sprintf("No ROMs found in %s.", console.Display))
+		return ROMFile{}, false
+	}
+	if saves, err := scanSaves(console.Path); err != nil {
+		logError("scanning saves", err)
+	} else {
+		crossReferenceSaves(roms, saves)
+	}
+	// Save state shortcuts are only offered when an emulator pak can load the state.
+	_, saveStates, err := saveStateEmuPak(console.Tag)
+	logError("finding a save state emulator pak", err)
+
+	var result *gaba.ListResult
+	sized := false
+	for {
+		bySize := settings.ROMSortOrder == ROMSortSizeDesc || settings.ROMSortOrder == ROMSortSizeAsc
+		if bySize && !sized {
+			fillFolderSizes(roms)
+			sized = true
+		}
+		sortROMs(roms, settings.ROMSortOrder)
+
+		items := make([]gaba.MenuItem, len(roms))
+		for i, r := range roms {
+			text := r.Display
+			if r.Subdir != "" {
+				text = strings.ReplaceAll(filepath.ToSlash(r.Subdir), "/", " / ") + " / " + text
+			}
+			switch {
+			case r.IsMultiDisc:
+				text += "  [Multi]"
+			case r.IsCueFolder:
+				text += "  [CUE]"
+			case r.IsArchive:
+				text += "  [Archive]"
+			}
+			if r.HasSave {
+				text += "  [Save]"
+			}
+			if r.IsDisabled {
+				text += "  [disabled]"
+			}
+			if bySize {
+				text += fmt.Sprintf("  [%.1f MB]", float64(r.SizeBytes)/(1<<20))
+			}
+			items[i] = gaba.MenuItem{Text: text}
+		}
+
+		title := console.Display
+		if settings.PinnedConsole == console.Name {
+			title = "[Pinned] " + title
+		}
+		opts := gaba.DefaultListOptions(title, items)
+		opts.ActionButton = constants.VirtualButtonX
+		opts.SecondaryActionButton = constants.VirtualButtonY
+		opts.FooterHelpItems = []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "Back"},
+			{ButtonName: "X", HelpText: "Sort: " + romSortLabel(settings.ROMSortOrder)},
+			{ButtonName: "Y", HelpText: "Add all"},
+		}
+		if saveStates {
+			opts.TertiaryActionButton = constants.VirtualButtonSelect
+			opts.FooterHelpItems = append(opts.FooterHelpItems, gaba.FooterHelpItem{ButtonName: "Select", HelpText: "Save state"})
+		}
+		opts.FooterHelpItems = append(opts.FooterHelpItems, gaba.FooterHelpItem{ButtonName: "A", HelpText: "Select"})
+
+		var err error
+		result, err = gaba.List(opts)
+		if isErrCancelled(err) {
+			return ROMFile{}, false
+		}
+		if err != nil {
+			return ROMFile{}, false
+		}
+		// X cycles the sort order and redraws the list.
+		if result.Action == gaba.ListActionTriggered {
+			settings.ROMSortOrder = nextROMSortOrder(settings.ROMSortOrder)
+			logAction("rom_sort_changed", "order", settings.ROMSortOrder)
+			logError("saving ROM sort order", saveSettings(settings))
+			continue
+		}
+		if result.Action == gaba.ListActionSecondaryTriggered {
+			addAllROMsFlow(console, roms)
+			return ROMFile{}, false
+		}
+		// Select makes a shortcut into one of the focused ROM's save states.
+		if result.Action == gaba.ListActionTertiaryTriggered {
+			if len(result.Selected) > 0 {
+				saveStateShortcutFlow(console, roms[result.Selected[0]])
+			}
+			continue
+		}
+		break
+	}
+	if len(result.Selected) == 0 {
+		return ROMFile{}, false
+	}
+
+	rom := roms[result.Selected[0]]
+	logAction("rom_selected", "index", result.Selected[0], "rom", rom.Path)
+	if rom.IsArchive {
+		log.Printf("ui: pick rom: %s is an archive; the emulator must support loading it directly", rom.Name)
+	}
+	return rom, true
+}
+
+// nextROMSortOrder returns the sort order after order in romSortOrders. An empty or
+// unknown order counts as alphabetical.
+func nextROMSortOrder(order string) string {
+	i := max(slices.Index(romSortOrders, order), 0)
+	return romSortOrders[(i+1)%len(romSortOrders)]
+}
+
+// romSortLabel returns the ROM picker footer text for a sort order.
+func romSortLabel(order string) string {
+	switch order {
+	case ROMSortSizeDesc:
+		return "Largest"
+	case ROMSortSizeAsc:
+		return "Smallest"
+	case ROMSortDateDesc:
+		return "Newest"
+	default:
+		return "A–Z"
+	}
+}
+
+// addAllConsoleShortcutsFlow is the main-menu batch flow: pick a console, then create a
+// shortcut for each of its ROMs via addAllROMsFlow.
+func addAllConsoleShortcutsFlow() {
+	console, ok := pickConsole()
+	if !ok {
+		return
+	}
+	settings := loadSettings()
+	roms, err := scanROMs(console.Path, settings.ShowHidden, settings.ScanDepth)
+	if err != nil {
+		logError("scanning ROMs", err)
+		showError("Could not read ROMs.")
+		return
+	}
+	addAllROMsFlow(console, roms)
+}
+
+// addAllROMsFlow creates a shortcut for every enabled ROM in console after a confirmation
+// showing the count and a position pick, with a progress bar while they are created.
+func addAllROMsFlow(console ConsoleDir, roms []ROMFile) {
+	count := 0
+	for _, r := range roms {
+		if !r.IsDisabled {
+			count++
+		}
+	}
+	if count == 0 {
+		showError(fmt.Sprintf("No ROMs found in %s.", console.Display))
+		return
+	}
+
+	msg := fmt.Sprintf("Create %d shortcuts for\n%s?\n\nROMs that already have a shortcut\nare skipped.", count, console.Display)
+	confirmed, err := confirmationMessage(msg,
+		[]gaba.FooterHelpItem{
+			{HelpText: "Cancel"},
+			{HelpText: "Continue", IsConfirmButton: true},
+		},
+	)
+	if isErrCancelled(err) || confirmed == nil || !confirmed.Confirmed {
+		return
+	}
+
+	pos, ok := pickPosition("", console.Tag, sortPrefixes{})
+	if !ok {
+		return
+	}
+
+	settings := loadSettings()
+	var created, skipped int
+	fraction := atomic.NewFloat64(0)
+	_, err = gaba.ProcessMessage(fmt.Sprintf("Creating %d shortcuts...", count),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true, ShowProgressBar: true, Progress: fraction},
+		func() (any, error) {
+			progress := make(chan MediaProgress)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for p := range progress {
+					fraction.Store(float64(p.Completed) / float64(p.Total))
+				}
+			}()
+			var err error
+			created, skipped, err = autoCreateShortcutsForConsole(console, pos, settings, progress)
+			<-done
+			return nil, err
+		},
+	)
+	if err != nil {
+		showOperationError("creating shortcuts for console", "create the shortcuts", err)
+		return
+	}
+	logAction("shortcuts_bulk_created", "console", console.Name, "position", pos, "created", created, "skipped", skipped)
+
+	confirmationMessage(
+		fmt.Sprintf("Created %d shortcut(s).\nSkipped %d (existing or disabled).", created, skipped),
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
+}
+
+// ── Deep links ───────────────────────────────────────────────
+
+// runDeepLink executes a parsed deep link. Returns false for unsupported actions so the
+// caller can fall back to the main menu.
+//
+//	add-rom?console=<console folder>&rom=<ROM file, relative to the console folder>
+//
+// rom is optional; without it the ROM picker opens for the given console.
+func runDeepLink(action string, params map[string]string) bool {
+	switch action {
+	case deepLinkAddROM:
+		settings := loadSettings()
+		console, err := findConsoleDir(params["console"], settings.ShowHidden, settings.sortPrefixes())
+		if err != nil {
+			logError("deep link", err)
+			showError(fmt.Sprintf("Console \"%s\" not found.", params["console"]))
+			return true
+		}
+		var rom ROMFile
+		if romName := params["rom"]; romName != "" {
+			rom, err = findROM(console, romName, settings.ShowHidden)
+			if err != nil {
+				logError("deep link", err)
+				showError(fmt.Sprintf("ROM \"%s\" not found in %s.", romName, console.Display))
+				return true
+			}
+		} else {
+			var ok bool
+			if rom, ok = pickROM(console); !ok {
+				return true
+			}
+		}
+		logAction("deep_link", "action", action, "console", console.Name, "rom", rom.Path)
+		confirmROMShortcutFlow(console, rom)
+		return true
+	default:
+		log.Printf("ui: unsupported deep link action %q", action)
+		return false
+	}
+}
+
+// ── Add Tool Shortcut flow ───────────────────────────────────
+
+func addToolShortcutFlow() {
+	if !requireBridgeEmu() {
+		return
+	}
+
+	tool, ok := pickTool()
+	if !ok {
+		return
+	}
+
+	displayName := tool.Display
+	tag := toolShortcutTag(tool.Path)
+	log.Printf("ui: add tool shortcut: tool=%s tag=%s", tool.Name, tag)
+
+	// Check if shortcut already exists
+	prefixes := loadSettings().sortPrefixes()
+	if shortcutInRoms(displayName, tag, prefixes) {
+		confirmationMessage(
+			fmt.Sprintf("A shortcut for \"%s\" already exists.", displayName),
+			[]gaba.FooterHelpItem{
+				{HelpText: "Back"},
+			},
+		)
+		return
+	}
+	discard, ok := offerTrashRestore(displayName, tag, prefixes)
+	if !ok {
+		return
+	}
+
+	// Warn about missing dependency paks
+	if missing := checkToolDependencies(tool); len(missing) > 0 {
+		log.Printf("ui: add tool shortcut: missing dependencies: %s", strings.Join(missing, ", "))
+		msg := fmt.Sprintf("%s requires paks that are not installed:\n\n%s\n\nThe shortcut may not work until they are added.",
+			tool.Name, strings.Join(missing, "\n"))
+		result, err := confirmationMessage(msg,
+			[]gaba.FooterHelpItem{
+				{HelpText: "Cancel"},
+				{HelpText: "Continue", IsConfirmButton: true},
+			},
+		)
+		if isErrCancelled(err) || result == nil || !result.Confirmed {
+			return
+		}
+	}
+
+	// Pick position
+	pos, ok := pickPosition(displayName, tag, prefixes)
+	if !ok {
+		return
+	}
+
+	// Optional environment variables for the bridge emu
+	env, ok := toolEnvStep()
+	if !ok {
+		return
+	}
+
+	romsDir, _, _ := getBasePaths()
+	baseFolderName := buildFolderName(pos, displayName, tag, prefixes)
+	folderName, err := resolveNameConflict(romsDir, baseFolderName)
+	if err != nil {
+		showOperationError("creating tool shortcut", "create the shortcut", err)
+		return
+	}
+
+	// Confirm creation
+	preview, runnable := toolLaunchPreview(tool.Path, env)
+	msg := fmt.Sprintf("Create shortcut?\n\n%s\n\nTool: %s\nRuns:\n%s",
+		folderName, tool.Name, preview)
+	if !runnable {
+		log.Printf("ui: add tool shortcut: %s/launch.sh is missing or not executable", tool.Path)
+		msg += "\n\nlaunch.sh is missing or not executable,\nso the shortcut will do nothing."
+	}
+	if tag == directBridgeEmuTag {
+		msg += "\n\nThis tool has its own " + directLaunchScript + ",\nso it launches through SHORTCUT_DIRECT.pak."
+	}
+	if folderName != baseFolderName {
+		msg += "\n\nA folder with that name already exists,\nso a number was added."
+	}
+
+	result, err := confirmationMessage(msg,
+		[]gaba.FooterHelpItem{
+			{HelpText: "Cancel"},
+			{HelpText: "Create", IsConfirmButton: true},
+		},
+	)
+	if isErrCancelled(err) || result == nil || !result.Confirmed {
+		return
+	}
+
+	// Paks with their own shortcut launcher need the second bridge.
+	if tag == directBridgeEmuTag {
+		if err := ensureDirectBridgeEmu(); err != nil {
+			showOperationError("installing direct bridge emulator", "install SHORTCUT_DIRECT.pak", err)
+			return
+		}
+	}
+
+	// Create shortcut
+	settings := loadSettings()
+	var artErr error
+	_, err = gaba.ProcessMessage("Creating shortcut...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			var err error
+			artErr, err = createToolShortcut(displayName, tool.Path, env, pos, settings)
+			logError("updating shortcut registry", updateShortcutRegistry(settings))
+			return nil, err
+		},
+	)
+	if err != nil {
+		showOperationError("creating tool shortcut", "create the shortcut", err)
+		return
+	}
+	logAction("shortcut_created", "type", "tool", "folder", folderName, "tool", tool.Path, "tag", tag, "env", len(env))
+	discardReplacedShortcut(discard)
+
+	confirmationMessage(
+		fmt.Sprintf("Shortcut created!\n\n%s\n\nwill appear on your main menu.%s", folderName, artworkNote(artErr)),
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
+}
+
+// toolEnvStep asks whether the tool needs extra environment variables and, if so,
+// opens the env editor. Returns false if the user backed out of the flow.
+func toolEnvStep() (map[string]string, bool) {
+	msg := "Set environment variables?\n\nSome tools need variables such as\nSDL_VIDEODRIVER set at launch."
+	result, err := confirmationMessage(msg,
+		[]gaba.FooterHelpItem{
+			{HelpText: "Skip"},
+			{HelpText: "Edit env", IsConfirmButton: true},
+		},
+	)
+	if isErrCancelled(err) || result == nil || !result.Confirmed {
+		return nil, true
+	}
+	return editEnvFlow(map[string]string{})
+}
+
+// editEnvFlow is a key-value list editor for environment variables.
+// A edits (or adds) an entry as KEY=VALUE, X removes it, B finishes editing.
+func editEnvFlow(env map[string]string) (map[string]string, bool) {
+	for {
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		items := make([]gaba.MenuItem, 0, len(keys)+1)
+		for _, k := range keys {
+			items = append(items, gaba.MenuItem{Text: k + "=" + env[k]})
+		}
+		items = append(items, gaba.MenuItem{Text: "+ Add variable"})
+
+		opts := gaba.DefaultListOptions("Environment", items)
+		opts.ActionButton = constants.VirtualButtonX
+		opts.FooterHelpItems = []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "Done"},
+			{ButtonName: "X", HelpText: "Remove"},
+			{ButtonName: "A", HelpText: "Edit"},
+		}
+
+		result, err := gaba.List(opts)
+		if isErrCancelled(err) {
+			return env, true
+		}
+		if err != nil || len(result.Selected) == 0 {
+			return nil, false
+		}
+
+		idx := result.Selected[0]
+		if result.Action == gaba.ListActionTriggered {
+			if idx < len(keys) {
+				log.Printf("ui: env remove %s", keys[idx])
+				delete(env, keys[idx])
+			}
+			continue
+		}
+
+		initial := ""
+		if idx < len(keys) {
+			initial = keys[idx] + "=" + env[keys[idx]]
+		}
+		kb, err := gaba.Keyboard(initial, "KEY=VALUE")
+		if isErrCancelled(err) || err != nil || kb == nil {
+			continue
+		}
+		key, value, found := strings.Cut(strings.TrimSpace(kb.Text), "=")
+		key = strings.TrimSpace(key)
+		if !found || !isValidEnvName(key) {
+			showError(fmt.Sprintf("\"%s\" is not a valid entry.\n\nUse KEY=VALUE, where KEY contains\nonly letters, digits and _.", kb.Text))
+			continue
+		}
+		if idx < len(keys) && keys[idx] != key {
+			delete(env, keys[idx])
+		}
+		env[key] = value
+		log.Printf("ui: env set %s", key)
+	}
+}
+
+// requireBridgeEmu checks that SHORTCUT.pak is installed. When it is missing it explains
+// why and offers to retry ensureBridgeEmu until it succeeds or the user cancels.
+func requireBridgeEmu() bool {
+	var installErr error
+	for !verifyBridgeEmu() {
+		log.Printf("ui: add tool shortcut: bridge emulator missing")
+		hint := "Check that the SD card is writable."
+		if installErr != nil {
+			hint = errorHint(installErr)
+		}
+		result, err := confirmationMessage(
+			"The SHORTCUT.pak bridge emulator could not be created.\n\nTool shortcuts will not launch without it.\n"+hint,
+			[]gaba.FooterHelpItem{
+				{HelpText: "Cancel"},
+				{HelpText: "Retry", IsConfirmButton: true},
+			},
+		)
+		if isErrCancelled(err) || result == nil || !result.Confirmed {
+			return false
+		}
+		logAction("bridge_emu_retry")
+		installErr = ensureBridgeEmu()
+		logError("ensuring bridge emulator", installErr)
+	}
+	return true
+}
+
+// toolLabel returns the tool picker text for t, badging built-in system paks and appending
+// the pak.json description when there is one.
+func toolLabel(t ToolPak) string {
+	label := t.Display
+	if t.Subdir != "" {
+		label = strings.ReplaceAll(filepath.ToSlash(t.Subdir), "/", " / ") + " / " + label
+	}
+	if t.IsSystem {
+		label += "  [System]"
+	}
+	if !t.IsShortcutTarget {
+		label += "  [No shortcut]"
+	}
+	if t.Description != "" {
+		label += " — " + t.Description
+	}
+	return label
+}
+
+func pickTool() (ToolPak, bool) {
+	settings := loadSettings()
+	tools, err := scanTools(settings.ShowHidden, settings.ScanToolsDeep)
+	if err != nil {
+		logError("scanning tools", err)
+		showError("Could not read Tools folder.")
+		return ToolPak{}, false
+	}
+	if settings.ScanSystemTools {
+		if system, err := scanSystemTools(); err != nil {
+			logError("scanning system tools", err)
+		} else if len(system) > 0 {
+			tools = append(tools, system...)
+			sort.SliceStable(tools, func(i, j int) bool {
+				return strings.ToLower(tools[i].Display) < strings.ToLower(tools[j].Display)
+			})
+		}
+	}
+	if len(tools) == 0 {
+		showError("No tools found.")
+		return ToolPak{}, false
+	}
+
+	selected := 0
+	for {
+		// Pinned tools first, then a divider, then the rest alphabetically.
+		// entries[i] is nil for the divider row, which can't be picked.
+		pinned, rest := splitPinnedTools(tools, settings.PinnedTools)
+		var items []gaba.MenuItem
+		var entries []*ToolPak
+		for i := range pinned {
+			items = append(items, gaba.MenuItem{Text: "[Pinned] " + toolLabel(pinned[i])})
+			entries = append(entries, &pinned[i])
+		}
+		if len(pinned) > 0 && len(rest) > 0 {
+			items = append(items, gaba.MenuItem{Text: "──── Other tools ────"})
+			entries = append(entries, nil)
+		}
+		for i := range rest {
+			items = append(items, gaba.MenuItem{Text: toolLabel(rest[i])})
+			entries = append(entries, &rest[i])
+		}
+
+		opts := gaba.DefaultListOptions("Select Tool", items)
+		opts.SelectedIndex = min(selected, len(items)-1)
+		opts.SecondaryActionButton = constants.VirtualButtonY
+		opts.FooterHelpItems = []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "Back"},
+			{ButtonName: "Y", HelpText: "Pin/Unpin"},
+			{ButtonName: "A", HelpText: "Select"},
+		}
+
+		result, err := gaba.List(opts)
+		if isErrCancelled(err) {
+			return ToolPak{}, false
+		}
+		if err != nil || len(result.Selected) == 0 {
+			return ToolPak{}, false
+		}
+
+		selected = result.Selected[0]
+		tool := entries[selected]
+		if tool == nil {
+			// Pressing A or Y on the divider only moves the cursor to the first tool below it.
+			log.Printf("ui: pick tool: divider selected")
+			selected++
+			continue
+		}
+
+		// Y pins or unpins the focused tool and redraws the list.
+		if result.Action == gaba.ListActionSecondaryTriggered {
+			name := filepath.Base(tool.Path)
+			settings.PinnedTools = togglePinnedTool(settings.PinnedTools, name)
+			showOperationError("saving pinned tools", "save settings", saveSettings(settings))
+			logAction("tool_pin_toggled", "tool", name, "pinned", isToolPinned(settings.PinnedTools, name))
+			// The tool moves between groups, so keep the cursor on it rather than its old row.
+			pinned, rest := splitPinnedTools(tools, settings.PinnedTools)
+			selected = pickToolListIndex(pinned, rest, tool.Path)
+			continue
+		}
+
+		// Paks marked no_shortcut stay listed, so it is clear why they have no shortcut, but
+		// can't be picked.
+		if !tool.IsShortcutTarget {
+			log.Printf("ui: pick tool: %s is marked no_shortcut", tool.Path)
+			showError(fmt.Sprintf("%s can't have a shortcut.\n\nIts pak.json marks it as a helper\nthat other tools launch themselves.", tool.Name))
+			continue
+		}
+
+		logAction("tool_selected", "index", selected, "tool", tool.Path)
+		return *tool, true
+	}
+}
+
+// ── Manage existing shortcuts ────────────────────────────────
+
+func manageShortcutsFlow() {
+	var tagFilter string // console tag filter; "" shows all shortcuts
+	var query string     // display-name search; "" shows all shortcuts
+	var posFilter *ShortcutPosition
+	// The cursor follows the last opened shortcut across refreshes by display name; when it
+	// is gone (deleted or renamed) it stays at the same row.
+	var lastDisplay string
+	lastIndex := 0
+	for {
+		settings := loadSettings()
+		shortcuts, err := scanShortcuts(settings)
+		if err != nil {
+			logError("scanning shortcuts", err)
+			showError("Could not read shortcuts.")
+			return
+		}
+		// An empty list stays open so a playlist can still be imported.
+		emptyMessage := "No shortcuts found.\n\nCreate one first!"
+		if len(shortcuts) > 0 {
+			emptyMessage = "No shortcuts match the filter or search."
+		}
+
+		title := "Manage Shortcuts"
+		if tagFilter != "" {
+			shortcuts = filterShortcutsByTag(shortcuts, tagFilter)
+			title += " [" + tagFilter + "]"
+		}
+		if posFilter != nil {
+			shortcuts = filterShortcutsByPosition(shortcuts, *posFilter)
+			title += " [" + positionLabel(*posFilter) + "]"
+		}
+		if query != "" {
+			shortcuts = searchShortcuts(shortcuts, query)
+			title += " \"" + query + "\""
+		}
+
+		items := make([]gaba.MenuItem, len(shortcuts))
+		for i, sc := range shortcuts {
+			text := shortcutMenuIcon(sc) + "  " + sc.Display
+			if sc.IsNameMismatch {
+				text += "  [Name mismatch]"
+			}
+			items[i] = gaba.MenuItem{Text: text}
+		}
+
+		filterHelp := "Filter"
+		searchHelp := "Search"
+		if query != "" {
+			searchHelp = "Clear search"
+		}
+		opts := gaba.DefaultListOptions(title, items)
+		opts.SelectedIndex = max(min(lastIndex, len(items)-1), 0)
+		if i := slices.IndexFunc(shortcuts, func(sc Shortcut) bool { return sc.Display == lastDisplay }); lastDisplay != "" && i >= 0 {
+			opts.SelectedIndex = i
+		}
+		opts.EmptyMessage = emptyMessage
+		opts.ActionButton = constants.VirtualButtonX
+		opts.SecondaryActionButton = constants.VirtualButtonY
+		opts.TertiaryActionButton = constants.VirtualButtonSelect
+		opts.FooterHelpItems = []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "Back"},
+			{ButtonName: "X", HelpText: filterHelp},
+			{ButtonName: "Y", HelpText: searchHelp},
+			{ButtonName: "Select", HelpText: "Import"},
+			{ButtonName: "A", HelpText: "Details"},
+		}
+
+		result, err := gaba.List(opts)
+		if isErrCancelled(err) {
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		// X opens the filter picker: by console tag, by position tier, or clear both.
+		if result.Action == gaba.ListActionTriggered {
+			switch pickShortcutFilter(tagFilter != "" || posFilter != nil) {
+			case shortcutFilterConsole:
+				if console, ok := pickConsole(); ok {
+					tagFilter = console.Tag
+					logAction("shortcut_filter_set", "tag", tagFilter)
+				}
+			case shortcutFilterPosition:
+				if pos, ok := pickPositionFilter(); ok {
+					posFilter = &pos
+					logAction("shortcut_filter_set", "position", pos)
+				}
+			case shortcutFilterClear:
+				tagFilter = ""
+				posFilter = nil
+				logAction("shortcut_filter_cleared")
+			}
+			continue
+		}
+
+		// Y toggles the name search: type a query, or clear the active one.
+		if result.Action == gaba.ListActionSecondaryTriggered {
+			if query != "" {
+				query = ""
+				logAction("shortcut_search_cleared")
+			} else if kb, err := gaba.Keyboard("", "Search"); err == nil && kb != nil {
+				query = strings.TrimSpace(kb.Text)
+				if query != "" {
+					logAction("shortcut_search_set", "query", query)
+				}
+			}
+			continue
+		}
+
+		// Select imports shortcuts from a RetroArch playlist.
+		if result.Action == gaba.ListActionTertiaryTriggered {
+			importPlaylistFlow()
+			continue
+		}
+		if len(result.Selected) == 0 {
+			return
+		}
+
+		idx := result.Selected[0]
+		lastDisplay, lastIndex = shortcuts[idx].Display, idx
+		log.Printf("ui: manage shortcuts -> selected index=%d name=%s", idx, shortcuts[idx].Display)
+		action := showShortcutDetail(shortcuts[idx])
+		if action == detailActionDeleted || action == detailActionBack {
+			// Refresh the list (deleted or went back from detail)
+			continue
+		}
+	}
+}
+
+// importPlaylistFlow lets the user pick a RetroArch .lpl playlist and creates a shortcut
+// for each entry that resolves to a ROM on the card.
+func importPlaylistFlow() {
+	files := findLPLFiles()
+	if len(files) == 0 {
+		showError("No RetroArch playlists found.\n\nCopy a .lpl file to Playlists/\non the SD card.")
+		return
+	}
+
+	items := make([]gaba.MenuItem, len(files))
+	for i, f := range files {
+		items[i] = gaba.MenuItem{Text: stripExtension(filepath.Base(f))}
+	}
+	opts := gaba.DefaultListOptions("Import from Retroarch playlist", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Import"},
+	}
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 {
+		return
+	}
+	lplPath := files[result.Selected[0]]
+
+	settings := loadSettings()
+	var imported, skipped int
+	_, err = gaba.ProcessMessage("Importing playlist...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			var err error
+			imported, skipped, err = importFromLPL(lplPath, settings)
+			return nil, err
+		},
+	)
+	if err != nil {
+		showOperationError("importing playlist", "import the playlist", err)
+		return
+	}
+	logAction("playlist_imported", "path", lplPath, "imported", imported, "skipped", skipped)
+
+	confirmationMessage(
+		fmt.Sprintf("Imported %d shortcut(s).\nSkipped %d (duplicates or ROMs not found).", imported, skipped),
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
+}
+
+// Nerd Font glyphs for the shortcut type, drawn by the fonts gabagool bundles. gaba.MenuItem
+// has no icon or color field, so the glyph is part of the item text.
+const (
+	romShortcutIcon  = "\uf11b" // nf-fa-gamepad
+	toolShortcutIcon = "\uf0ad" // nf-fa-wrench
+)
+
+// shortcutMenuIcon returns the Manage Shortcuts list icon for sc's type.
+func shortcutMenuIcon(sc Shortcut) string {
+	if sc.IsTool {
+		return toolShortcutIcon
+	}
+	return romShortcutIcon
+}
+
+type shortcutFilter int
+
+const (
+	shortcutFilterNone shortcutFilter = iota
+	shortcutFilterConsole
+	shortcutFilterPosition
+	shortcutFilterClear
+)
+
+// pickShortcutFilter asks which filter to apply to the Manage Shortcuts list.
+// "Clear filters" is only offered when a filter is active.
+func pickShortcutFilter(active bool) shortcutFilter {
+	choices := []shortcutFilter{shortcutFilterConsole, shortcutFilterPosition}
+	items := []gaba.MenuItem{
+		{Text: "By console"},
+		{Text: "By position"},
+	}
+	if active {
+		choices = append(choices, shortcutFilterClear)
+		items = append(items, gaba.MenuItem{Text: "Clear filters"})
+	}
+	opts := gaba.DefaultListOptions("Filter Shortcuts", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 {
+		return shortcutFilterNone
+	}
+	return choices[result.Selected[0]]
+}
+
+// pickPositionFilter asks which position tier to show.
+func pickPositionFilter() (ShortcutPosition, bool) {
+	positions := []ShortcutPosition{ShortcutPositionTop, ShortcutPositionBottom, ShortcutPositionAlpha}
+	items := make([]gaba.MenuItem, len(positions))
+	for i, pos := range positions {
+		items[i] = gaba.MenuItem{Text: positionLabel(pos)}
+	}
+	opts := gaba.DefaultListOptions("Filter by Position", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 {
+		return ShortcutPositionBottom, false
+	}
+	return positions[result.Selected[0]], true
+}
+
+// positionLabel returns the user-facing name of a position tier.
+func positionLabel(pos ShortcutPosition) string {
+	switch pos {
+	case ShortcutPositionTop:
+		return "Top"
+	case ShortcutPositionAlpha:
+		return "Alpha"
+	default:
+		return "Bottom"
+	}
+}
+
+type detailAction int
+
+const (
+	detailActionBack detailAction = iota
+	detailActionDeleted
+)
 
 func showShortcutDetail(sc Shortcut) detailAction {
 	kind := "ROM"
-	if sc.IsTool {
+	switch {
+	case sc.IsTool:
 		kind = "Tool"
+	case sc.IsSaveState:
+		kind = "Save state"
+	}
+
+	metadata := []gaba.MetadataItem{
+		{Label: "Name", Value: sc.Display},
+		{Label: "Type", Value: kind},
+		{Label: "Tag", Value: sc.Tag},
+	}
+
+	if sc.TargetPath != "" {
+		metadata = append(metadata, gaba.MetadataItem{
+			Label: "Target", Value: sc.TargetPath,
+		})
+	}
+	if sc.IsTool {
+		// The tool's current pak.json wins; the marker keeps what it said at creation, for
+		// tools that have since been removed or lost their pak.json.
+		description, version := sc.Description, sc.Version
+		if meta, err := readPakMeta(sc.TargetPath); err == nil {
+			description = cmp.Or(strings.TrimSpace(meta.Description), description)
+			version = cmp.Or(strings.TrimSpace(meta.Version), version)
+		}
+		if description != "" {
+			metadata = append(metadata, gaba.MetadataItem{Label: "Description", Value: description})
+		}
+		if version != "" {
+			metadata = append(metadata, gaba.MetadataItem{Label: "Version", Value: version})
+		}
+	}
+
+	// Markers from older versions lack the creation time; the folder's mtime is the best guess.
+	created := sc.CreatedAt
+	if created.IsZero() {
+		if info, err := os.Stat(sc.Path); err == nil {
+			created = info.ModTime()
+		}
+	}
+	if !created.IsZero() {
+		metadata = append(metadata, gaba.MetadataItem{
+			Label: "Created", Value: created.Local().Format("2006-01-02 15:04"),
+		})
+	}
+
+	launches := fmt.Sprintf("%d", sc.AccessCount)
+	if sc.AccessCount > 0 {
+		launches += " (last " + sc.LastAccessed.Local().Format("2006-01-02 15:04") + ")"
+	}
+	metadata = append(metadata, gaba.MetadataItem{Label: "Launches", Value: launches})
+
+	envKeys := make([]string, 0, len(sc.Env))
+	for k := range sc.Env {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		metadata = append(metadata, gaba.MetadataItem{
+			Label: "Env", Value: k + "=" + sc.Env[k],
+		})
+	}
+
+	sections := []gaba.Section{
+		gaba.NewInfoSection("Shortcut Info", metadata),
+	}
+
+	detailOpts := gaba.DefaultInfoScreenOptions()
+	detailOpts.Sections = sections
+	detailOpts.ShowThemeBackground = true
+	detailOpts.ShowScrollbar = false
+	detailOpts.ConfirmButton = constants.VirtualButtonA
+	detailOpts.AllowAction = true
+	detailOpts.ActionButton = constants.VirtualButtonY
+
+	footer := []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "Y", HelpText: "Artwork"},
+		{ButtonName: "A", HelpText: "Actions", IsConfirmButton: true},
+	}
+
+	result, err := gaba.DetailScreen(sc.Display, detailOpts, footer)
+	if isErrCancelled(err) {
+		return detailActionBack
+	}
+	if err == nil && result != nil && result.Action == gaba.DetailActionTriggered {
+		shortcutArtworkFlow(sc)
+		return detailActionBack
+	}
+
+	// User pressed A — pick rename or delete, or fix a name mismatch
+	items := []gaba.MenuItem{{Text: "Rename"}, {Text: "Delete"}}
+	if sc.IsNameMismatch {
+		items = append(items, gaba.MenuItem{Text: "Fix name mismatch"})
+	}
+	opts := gaba.DefaultListOptions(sc.Display, items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	choice, err := gaba.List(opts)
+	if err != nil || len(choice.Selected) == 0 {
+		return detailActionBack
+	}
+	switch choice.Selected[0] {
+	case 0:
+		renameShortcutFlow(sc)
+		return detailActionBack
+	case 2:
+		fixNameMismatchFlow(sc)
+		return detailActionBack
+	}
+	return confirmDelete(sc)
+}
+
+// fixNameMismatchFlow makes a shortcut's folder name and marker agree again, keeping
+// whichever name the user picks: the folder's (the marker is updated) or the marker's
+// (the folder and .m3u are renamed).
+func fixNameMismatchFlow(sc Shortcut) {
+	settings := loadSettings()
+	prefixes := settings.sortPrefixes()
+	_, prefix := prefixes.match(sc.Name)
+	folderName := folderDisplayName(sc.Name, prefix)
+
+	items := []gaba.MenuItem{
+		{Text: "Use folder name: " + folderName},
+		{Text: "Use marker name: " + sc.Display},
+	}
+	opts := gaba.DefaultListOptions("Fix Name Mismatch", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Cancel"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	choice, err := gaba.List(opts)
+	if err != nil || len(choice.Selected) == 0 {
+		return
+	}
+
+	keep := folderName
+	if choice.Selected[0] == 1 {
+		keep = sc.Display
+	}
+	fixed, err := renameShortcut(sc, keep, prefixes)
+	if err != nil {
+		showOperationError("fixing shortcut name mismatch", "fix the shortcut name", err)
+		return
+	}
+	logError("updating shortcut registry", updateShortcutRegistry(settings))
+	logAction("shortcut_name_fixed", "from", sc.Name, "to", fixed.Name, "display", fixed.Display)
+}
+
+// renameShortcutFlow asks for a new display name and renames the shortcut's folder, .m3u
+// and marker. When source art exists under the new name, the user can also regenerate
+// bg.png from it.
+func renameShortcutFlow(sc Shortcut) {
+	newDisplay, ok := textInput(sc.Display, "Shortcut name", maxDisplayNameLen)
+	if !ok || newDisplay == sc.Display {
+		return
+	}
+
+	settings := loadSettings()
+	prefixes := settings.sortPrefixes()
+	newName := buildFolderName(sc.Position, newDisplay, sc.Tag, prefixes)
+	// A case-only change finds the shortcut itself on FAT, which is case-insensitive.
+	if _, err := os.Stat(filepath.Join(filepath.Dir(sc.Path), newName)); err == nil && !strings.EqualFold(newName, sc.Name) {
+		showError(fmt.Sprintf("A shortcut named \"%s\"\nalready exists.", newDisplay))
+		return
+	}
+
+	// Only offer regenerating when there is source art. ROM art is found by the ROM's name,
+	// but tool art by the display name, so a renamed tool may have none (see shortcutArtName).
+	renamed := sc
+	renamed.Display = newDisplay
+	items := []gaba.MenuItem{{Text: "Rename"}}
+	if fileExists(firstExistingPath(resolveArtSrcPaths(renamed, loadSettings()))) {
+		items = append(items, gaba.MenuItem{Text: "Rename and regenerate art"})
+	}
+	opts := gaba.DefaultListOptions(fmt.Sprintf("Rename to \"%s\"?", newDisplay), items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Cancel"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	choice, err := gaba.List(opts)
+	if err != nil || len(choice.Selected) == 0 {
+		return
+	}
+	regenerate := choice.Selected[0] == 1
+
+	// The rename stands even when the artwork fails, so that error is reported separately.
+	var artErr error
+	_, err = gaba.ProcessMessage("Renaming shortcut...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			var err error
+			if renamed, err = renameShortcut(sc, newDisplay, prefixes); err != nil {
+				return nil, err
+			}
+			logError("updating shortcut registry", updateShortcutRegistry(settings))
+			if regenerate {
+				totals, err := regenerateMedia([]Shortcut{renamed}, settings, nil)
+				if err == nil && totals.Failed > 0 {
+					err = errors.New("artwork generation failed; see the log")
+				}
+				artErr = err
+			}
+			return nil, nil
+		},
+	)
+	if err != nil {
+		showOperationError("renaming shortcut", "rename the shortcut", err)
+		return
+	}
+	logAction("shortcut_renamed", "from", sc.Name, "to", renamed.Name, "regenerateArt", regenerate)
+	if artErr != nil {
+		logError("regenerating renamed shortcut artwork", artErr)
+		showError("The shortcut was renamed, but its\nartwork could not be regenerated.")
+	}
+}
+
+// cycleArtworkFlow edits the extra artwork pages (bg2.png, bg3.png, ...) of a shortcut.
+// Sources are picked from the folder holding its main art; X removes a page and B saves.
+// The pages are generated right away when Multi-page artwork is on.
+func cycleArtworkFlow(sc Shortcut) {
+	artSrc := shortcutArtSrcPath(sc, loadSettings())
+	if artSrc == "" {
+		showError("Could not find the artwork folder\nfor this shortcut.")
+		return
+	}
+	meta, err := readShortcutMeta(sc.Path)
+	if err != nil {
+		logError("reading shortcut marker", err)
+		showError("Could not read the shortcut's\n.shortcut file.")
+		return
+	}
+	pages := slices.Clone(meta.AdditionalArtPaths)
+
+	for {
+		items := make([]gaba.MenuItem, 0, len(pages)+1)
+		for i, p := range pages {
+			items = append(items, gaba.MenuItem{Text: fmt.Sprintf("Page %d: %s", i+2, filepath.Base(p))})
+		}
+		items = append(items, gaba.MenuItem{Text: "+ Add image"})
+
+		opts := gaba.DefaultListOptions("Cycle Artwork", items)
+		opts.ActionButton = constants.VirtualButtonX
+		opts.FooterHelpItems = []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "Done"},
+			{ButtonName: "X", HelpText: "Remove"},
+			{ButtonName: "A", HelpText: "Add"},
+		}
+
+		result, err := gaba.List(opts)
+		if isErrCancelled(err) {
+			break
+		}
+		if err != nil || len(result.Selected) == 0 {
+			return
+		}
+
+		idx := result.Selected[0]
+		if result.Action == gaba.ListActionTriggered {
+			if idx < len(pages) {
+				log.Printf("ui: cycle artwork remove %s", pages[idx])
+				pages = slices.Delete(pages, idx, idx+1)
+			}
+			continue
+		}
+		if idx == len(pages) {
+			if path, ok := pickArtImage(filepath.Dir(artSrc), append([]string{artSrc}, pages...)); ok {
+				log.Printf("ui: cycle artwork add %s", path)
+				pages = append(pages, path)
+			}
+		}
+	}
+
+	if slices.Equal(pages, meta.AdditionalArtPaths) {
+		return
+	}
+	err = updateShortcutMeta(sc.Path, func(m *ShortcutMeta) bool {
+		m.AdditionalArtPaths = pages
+		return true
+	})
+	if err != nil {
+		showOperationError("saving artwork pages", "save the artwork pages", err)
+		return
+	}
+	logAction("art_pages_set", "folder", sc.Name, "pages", len(pages))
+
+	if !loadSettings().MultiPageArt {
+		confirmationMessage(
+			"Artwork pages saved.\n\nTurn on Multi-page artwork in\nSettings to generate them.",
+			[]gaba.FooterHelpItem{
+				{HelpText: "OK", IsConfirmButton: true},
+			},
+		)
+		return
+	}
+	regenerateShortcutArtwork(sc)
+}
+
+// shortcutArtworkFlow is the detail screen's artwork menu for one shortcut: its artwork
+// mode override, its extra pages (cycleArtworkFlow) and regenerating its artwork.
+func shortcutArtworkFlow(sc Shortcut) {
+	for {
+		meta, err := readShortcutMeta(sc.Path)
+		if err != nil {
+			logError("reading shortcut marker", err)
+			showError("Could not read the shortcut's\n.shortcut file.")
+			return
+		}
+		mode := "Global (" + artworkModeLabel(loadSettings().ArtworkMode) + ")"
+		if meta.ArtworkModeOverride != nil {
+			mode = artworkModeLabel(*meta.ArtworkModeOverride)
+		}
+		items := []gaba.MenuItem{
+			{Text: "Artwork mode: " + mode},
+			{Text: fmt.Sprintf("Extra pages (%d)", len(meta.AdditionalArtPaths))},
+			{Text: "Regenerate artwork"},
+		}
+		opts := gaba.DefaultListOptions("Artwork", items)
+		opts.FooterHelpItems = []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "Back"},
+			{ButtonName: "A", HelpText: "Select"},
+		}
+		result, err := gaba.List(opts)
+		if err != nil || len(result.Selected) == 0 {
+			return
+		}
+
+		switch result.Selected[0] {
+		case 0:
+			meta.ArtworkModeOverride = nextArtworkModeOverride(meta.ArtworkModeOverride)
+			err := updateShortcutMeta(sc.Path, func(m *ShortcutMeta) bool {
+				m.ArtworkModeOverride = meta.ArtworkModeOverride
+				return true
+			})
+			if err != nil {
+				showOperationError("saving artwork mode", "save the artwork mode", err)
+				continue
+			}
+			override := "global"
+			if meta.ArtworkModeOverride != nil {
+				override = artworkModeLabel(*meta.ArtworkModeOverride)
+			}
+			logAction("artwork_mode_override", "folder", sc.Name, "mode", override)
+		case 1:
+			cycleArtworkFlow(sc)
+		case 2:
+			regenerateShortcutArtwork(sc)
+		}
+	}
+}
+
+// artworkModeOverrides is the cycle order of a shortcut's artwork mode; nil is the global
+// setting.
+var artworkModeOverrides = []int{ArtworkModeBlack, ArtworkModeWallpaper, ArtworkModeFallback}
+
+// nextArtworkModeOverride returns the override after cur: global, then each mode in
+// artworkModeOverrides, then back to global.
+func nextArtworkModeOverride(cur *int) *int {
+	next := 0
+	if cur != nil {
+		i := slices.Index(artworkModeOverrides, *cur)
+		if i < 0 || i+1 >= len(artworkModeOverrides) {
+			return nil
+		}
+		next = i + 1
+	}
+	mode := artworkModeOverrides[next]
+	return &mode
+}
+
+// artworkModeLabel returns a short name for an ArtworkMode* value.
+func artworkModeLabel(mode int) string {
+	switch mode {
+	case ArtworkModeWallpaper:
+		return "Wallpaper"
+	case ArtworkModeFallback:
+		return "Fallback"
+	default:
+		return "Black"
+	}
+}
+
+// regenerateShortcutArtwork regenerates one shortcut's artwork, honouring its artwork mode
+// override and extra pages, and reports the outcome.
+func regenerateShortcutArtwork(sc Shortcut) {
+	settings := loadSettings()
+	var totals ArtTotals
+	_, err := gaba.ProcessMessage("Generating artwork...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			var err error
+			totals, err = regenerateMedia([]Shortcut{sc}, settings, nil)
+			if err == nil && totals.Failed > 0 {
+				err = errors.New("artwork generation failed; see the log")
+			}
+			return nil, err
+		},
+	)
+	if err != nil {
+		showOperationError("regenerating shortcut artwork", "generate the artwork", err)
+		return
+	}
+	logAction("shortcut_artwork_regenerated", "folder", sc.Name, "generated", totals.Generated)
+
+	msg := "Artwork regenerated."
+	if totals.Generated == 0 {
+		msg = "No artwork was written.\n\nThere is no source art, or\nhand-made artwork was kept."
+	}
+	confirmationMessage(msg,
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
+}
+
+// pickArtImage lists the images in dir, minus those in exclude, and returns the chosen path.
+func pickArtImage(dir string, exclude []string) (string, bool) {
+	var paths []string
+	for _, p := range listArtImages(dir) {
+		if !slices.Contains(exclude, p) {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		showError(fmt.Sprintf("No other images found in\n%s", dir))
+		return "", false
+	}
+
+	items := make([]gaba.MenuItem, len(paths))
+	for i, p := range paths {
+		items[i] = gaba.MenuItem{Text: filepath.Base(p)}
+	}
+	opts := gaba.DefaultListOptions("Add Image", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 {
+		return "", false
+	}
+	return paths[result.Selected[0]], true
+}
+
+func confirmDelete(sc Shortcut) detailAction {
+	settings := loadSettings()
+	msg := fmt.Sprintf("Delete shortcut?\n\n%s\n\nThis will remove the shortcut\nfrom the main menu.", sc.Display)
+
+	result, err := confirmationMessage(msg,
+		[]gaba.FooterHelpItem{
+			{HelpText: "Cancel"},
+			{HelpText: "Delete", IsConfirmButton: true},
+		},
+	)
+	if isErrCancelled(err) || result == nil || !result.Confirmed {
+		return detailActionBack
+	}
+
+	// Delete the shortcut
+	_, err = gaba.ProcessMessage("Removing shortcut...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			err := removeShortcut(sc.Path)
+			logError("updating shortcut registry", updateShortcutRegistry(settings))
+			return nil, err
+		},
+	)
+	if err != nil {
+		showOperationError("removing shortcut", "remove the shortcut", err)
+		return detailActionBack
+	}
+	logAction("shortcut_deleted", "folder", sc.Name, "target", sc.TargetPath)
+
+	confirmationMessage(
+		"Shortcut removed.",
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
+
+	return detailActionDeleted
+}
+
+// ── Settings screen ──────────────────────────────────────────
+
+// showSettingsScreen presents the global settings screen.
+// Users cycle Left/Right to change values and press A to save, or B to discard.
+// Clickable rows save the edits, run their sub-flow and then show the screen again.
+func showSettingsScreen() {
+	for {
+		settings := loadSettings()
+		rows := settingsRows(settings)
+		items := make([]gaba.ItemWithOptions, len(rows))
+		for i, row := range rows {
+			items[i] = row.item
+		}
+
+		// B always goes back in an options list, so a B confirm setting saves with Start here.
+		saveButton, _ := dialogButtons(settings)
+		if saveButton == constants.VirtualButtonB {
+			saveButton = constants.VirtualButtonStart
+		}
+		listOpts := gaba.OptionListSettings{
+			ConfirmButton: saveButton,
+			FooterHelpItems: []gaba.FooterHelpItem{
+				{ButtonName: "B", HelpText: "Back"},
+				{ButtonName: "←/→", HelpText: "Change"},
+				{ButtonName: virtualButtonName(saveButton), HelpText: "Save"},
+			},
+		}
+
+		result, err := gaba.OptionsList("Settings", listOpts, items)
+		if isErrCancelled(err) {
+			logAction("settings_discarded")
+			return // B pressed — discard changes
+		}
+		if err != nil {
+			logError("settings screen", err)
+			return
+		}
+		if result == nil {
+			return
+		}
+
+		for i, row := range rows {
+			if row.apply == nil || i >= len(result.Items) {
+				continue
+			}
+			item := result.Items[i]
+			if item.SelectedOption < 0 || item.SelectedOption >= len(item.Options) {
+				continue
+			}
+			row.apply(&settings, item.Options[item.SelectedOption].Value)
+		}
+		logAction("settings_changed", "copyArtwork", settings.CopyArtwork,
+			"artworkMode", settings.ArtworkMode, "showHidden", settings.ShowHidden,
+			"artAutoCrop", settings.ArtAutoCrop, "artSharpen", settings.ArtSharpen,
+			"artFit", settings.ArtFit, "artTextOverlay", settings.ArtTextOverlay,
+			"artTextPosition", settings.ArtTextPosition, "displayNameTemplate", settings.DisplayNameTemplate,
+			"scanSystemTools", settings.ScanSystemTools, "artTintGlobalBg", settings.ArtTintGlobalBg,
+			"artTintStrength", settings.ArtTintStrength,
+			"scanDepth", settings.ScanDepth, "scanToolsDeep", settings.ScanToolsDeep,
+			"skipUserCrafted", settings.SkipUserCrafted, "multiPageArt", settings.MultiPageArt,
+			"pinnedConsole", settings.PinnedConsole, "confirmButton", settings.ConfirmButton)
+		showOperationError("saving settings", "save settings", saveSettings(settings))
+
+		if result.Action == gaba.ListActionSelected && result.Selected >= 0 && result.Selected < len(rows) {
+			if open := rows[result.Selected].open; open != nil {
+				open()
+				continue
+			}
+		}
+		return
+	}
+}
+
+// settingRow is one row of the settings screen. apply writes the chosen option's Value
+// into the settings; clickable rows have open instead, the sub-flow they start.
+type settingRow struct {
+	item  gaba.ItemWithOptions
+	apply func(s *AppSettings, value any)
+	open  func()
+}
+
+// toggleRow returns an Off/On settings row showing on and setting it with set.
+func toggleRow(text string, on bool, set func(s *AppSettings, on bool)) settingRow {
+	selected := 0
+	if on {
+		selected = 1
+	}
+	return settingRow{
+		item: gaba.ItemWithOptions{
+			Item: gaba.MenuItem{Text: text},
+			Options: []gaba.Option{
+				{DisplayName: "Off", Value: false},
+				{DisplayName: "On", Value: true},
+			},
+			SelectedOption: selected,
+		},
+		apply: func(s *AppSettings, value any) {
+			on, _ := value.(bool)
+			set(s, on)
+		},
+	}
+}
+
+// settingsRows returns the rows of the settings screen for settings, in display order.
+func settingsRows(settings AppSettings) []settingRow {
+	// Pinned console: the only way to change it here is to unpin.
+	pinnedOptions := []gaba.Option{{DisplayName: "None", Value: ""}}
+	initialPinned := 0
+	if settings.PinnedConsole != "" {
+		pinnedOptions = append(pinnedOptions, gaba.Option{DisplayName: settings.PinnedConsole, Value: settings.PinnedConsole})
+		initialPinned = 1
+	}
+
+	initialArtFit := 0
+	if settings.ArtFit == ArtFitCover {
+		initialArtFit = 1
+	}
+
+	initialConfirmB := virtualButtonFromString(settings.ConfirmButton) == constants.VirtualButtonB
+	initialConfirm := 0
+	if initialConfirmB {
+		initialConfirm = 1
+	}
+
+	nameTemplate := settings.DisplayNameTemplate
+	if nameTemplate == "" {
+		nameTemplate = defaultDisplayNameTemplate
+	}
+
+	captionOptions := []gaba.Option{
+		{DisplayName: "Off", Value: ""},
+		{DisplayName: "Bottom left", Value: ArtTextBottomLeft},
+		{DisplayName: "Bottom center", Value: ArtTextBottomCenter},
+		{DisplayName: "Top left", Value: ArtTextTopLeft},
+	}
+	initialCaption := 0
+	if settings.ArtTextOverlay {
+		initialCaption = 1
+		for i, o := range captionOptions {
+			if o.Value == settings.ArtTextPosition {
+				initialCaption = i
+			}
+		}
+	}
+
+	sharpenOptions := []gaba.Option{
+		{DisplayName: "Off", Value: 0.0},
+		{DisplayName: "Low", Value: 0.25},
+		{DisplayName: "Medium", Value: 0.5},
+		{DisplayName: "High", Value: 1.0},
+	}
+	initialSharpen := 0
+	for i, o := range sharpenOptions {
+		if v, _ := o.Value.(float64); v <= settings.ArtSharpen {
+			initialSharpen = i
+		}
+	}
+
+	tintOptions := []gaba.Option{
+		{DisplayName: "Off", Value: 0.0},
+		{DisplayName: "Light", Value: 0.15},
+		{DisplayName: "Medium", Value: 0.3},
+		{DisplayName: "Strong", Value: 0.5},
+	}
+	initialTint := 0
+	if settings.ArtTintGlobalBg {
+		for i, o := range tintOptions {
+			if v, _ := o.Value.(float64); v > 0 && v <= settings.ArtTintStrength {
+				initialTint = i
+			}
+		}
+	}
+
+	depthOptions := []gaba.Option{
+		{DisplayName: "Top level only", Value: 0},
+		{DisplayName: "1 level", Value: 1},
+		{DisplayName: "2 levels", Value: 2},
+		{DisplayName: "All", Value: -1},
+	}
+	initialDepth := len(depthOptions) - 1
+	for i, o := range depthOptions {
+		if o.Value == settings.ScanDepth {
+			initialDepth = i
+		}
+	}
+
+	return []settingRow{
+		toggleRow("Copy artwork when available", settings.CopyArtwork,
+			func(s *AppSettings, on bool) { s.CopyArtwork = on }),
+		{
+			item: gaba.ItemWithOptions{
+				Item: gaba.MenuItem{Text: "Artwork mode"},
+				Options: []gaba.Option{
+					{DisplayName: "Art on Black background", Value: ArtworkModeBlack},
+					{DisplayName: "Art on Main menu Wallpaper", Value: ArtworkModeWallpaper},
+					{DisplayName: "Fallback to wallpaper", Value: ArtworkModeFallback},
+				},
+				SelectedOption: settings.ArtworkMode,
+			},
+			apply: func(s *AppSettings, value any) { s.ArtworkMode, _ = value.(int) },
+		},
+		toggleRow("Show hidden/disabled/empty ROMs", settings.ShowHidden,
+			func(s *AppSettings, on bool) { s.ShowHidden = on }),
+		toggleRow("Auto-crop artwork borders", settings.ArtAutoCrop,
+			func(s *AppSettings, on bool) { s.ArtAutoCrop = on }),
+		{
+			item: gaba.ItemWithOptions{
+				Item:           gaba.MenuItem{Text: "Sharpen artwork"},
+				Options:        sharpenOptions,
+				SelectedOption: initialSharpen,
+			},
+			apply: func(s *AppSettings, value any) { s.ArtSharpen, _ = value.(float64) },
+		},
+		{
+			item: gaba.ItemWithOptions{
+				Item: gaba.MenuItem{Text: "Artwork fit"},
+				Options: []gaba.Option{
+					{DisplayName: "Fit (show whole image)", Value: ArtFitFit},
+					{DisplayName: "Cover (fill and crop)", Value: ArtFitCover},
+				},
+				SelectedOption: initialArtFit,
+			},
+			apply: func(s *AppSettings, value any) { s.ArtFit, _ = value.(string) },
+		},
+		{
+			item: gaba.ItemWithOptions{
+				Item:           gaba.MenuItem{Text: "Show name on artwork"},
+				Options:        captionOptions,
+				SelectedOption: initialCaption,
+			},
+			apply: func(s *AppSettings, value any) {
+				captionPos, _ := value.(string)
+				s.ArtTextOverlay = captionPos != ""
+				if s.ArtTextOverlay {
+					s.ArtTextPosition = captionPos
+				}
+			},
+		},
+		{
+			item: gaba.ItemWithOptions{
+				Item: gaba.MenuItem{Text: "Display name template"},
+				Options: []gaba.Option{
+					{DisplayName: nameTemplate, Value: nameTemplate, Type: gaba.OptionTypeKeyboard, KeyboardPrompt: nameTemplate},
+				},
+			},
+			apply: func(s *AppSettings, value any) {
+				template, _ := value.(string)
+				s.DisplayNameTemplate = strings.TrimSpace(template)
+				if s.DisplayNameTemplate == defaultDisplayNameTemplate {
+					s.DisplayNameTemplate = ""
+				}
+			},
+		},
+		toggleRow("Include system tools", settings.ScanSystemTools,
+			func(s *AppSettings, on bool) { s.ScanSystemTools = on }),
+		{
+			item: gaba.ItemWithOptions{
+				Item:           gaba.MenuItem{Text: "Tint wallpaper with art color"},
+				Options:        tintOptions,
+				SelectedOption: initialTint,
+			},
+			apply: func(s *AppSettings, value any) {
+				tint, _ := value.(float64)
+				s.ArtTintGlobalBg = tint > 0
+				if s.ArtTintGlobalBg {
+					s.ArtTintStrength = tint
+				}
+			},
+		},
+		{
+			item: gaba.ItemWithOptions{
+				Item:           gaba.MenuItem{Text: "ROM subfolder depth"},
+				Options:        depthOptions,
+				SelectedOption: initialDepth,
+			},
+			apply: func(s *AppSettings, value any) { s.ScanDepth, _ = value.(int) },
+		},
+		toggleRow("Include tools in subfolders", settings.ScanToolsDeep,
+			func(s *AppSettings, on bool) { s.ScanToolsDeep = on }),
+		toggleRow("Keep hand-made artwork", settings.SkipUserCrafted,
+			func(s *AppSettings, on bool) { s.SkipUserCrafted = on }),
+		toggleRow("Multi-page artwork", settings.MultiPageArt,
+			func(s *AppSettings, on bool) { s.MultiPageArt = on }),
+		{
+			item: gaba.ItemWithOptions{
+				Item:           gaba.MenuItem{Text: "Pinned console"},
+				Options:        pinnedOptions,
+				SelectedOption: initialPinned,
+			},
+			apply: func(s *AppSettings, value any) { s.PinnedConsole, _ = value.(string) },
+		},
+		{
+			item: gaba.ItemWithOptions{
+				Item: gaba.MenuItem{Text: "Confirm button"},
+				Options: []gaba.Option{
+					{DisplayName: "A", Value: ""},
+					{DisplayName: "B", Value: "B"},
+				},
+				SelectedOption: initialConfirm,
+			},
+			// Other names set by hand in settings.json are kept unless the choice changed.
+			apply: func(s *AppSettings, value any) {
+				if confirmB, _ := value.(string); (confirmB == "B") != initialConfirmB {
+					s.ConfirmButton = confirmB
+				}
+			},
+		},
+		{
+			item: gaba.ItemWithOptions{
+				Item: gaba.MenuItem{Text: "Reorder consoles"},
+				Options: []gaba.Option{
+					{DisplayName: "Open", Type: gaba.OptionTypeClickable},
+				},
+			},
+			open: reorderConsolesFlow,
+		},
+	}
+}
+
+// reorderConsolesFlow lets the user move consoles up or down in the console picker.
+// Select toggles move mode on the focused console, A saves the order, X resets to
+// alphabetical and B discards.
+func reorderConsolesFlow() {
+	settings := loadSettings()
+	consoles, err := scanConsoleDirs(settings.ShowHidden, settings.sortPrefixes())
+	if err != nil {
+		logError("scanning consoles", err)
+		showError("Could not read ROM folders.")
+		return
+	}
+	if len(consoles) == 0 {
+		showError("No ROM folders found.")
+		return
+	}
+	sortConsolesByOrder(consoles, settings.ConsoleOrder)
+
+	items := make([]gaba.MenuItem, len(consoles))
+	for i, c := range consoles {
+		items[i] = gaba.MenuItem{Text: c.Display, Metadata: c.Display}
+	}
+	opts := gaba.DefaultListOptions("Reorder Consoles", items)
+	opts.ReorderButton = constants.VirtualButtonSelect
+	opts.ActionButton = constants.VirtualButtonX
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Cancel"},
+		{ButtonName: "Select", HelpText: "Move"},
+		{ButtonName: "X", HelpText: "Reset"},
+		{ButtonName: "A", HelpText: "Save"},
+	}
+
+	result, err := gaba.List(opts)
+	if err != nil || result == nil {
+		return // B pressed — discard
+	}
+	if result.Action == gaba.ListActionTriggered {
+		settings.ConsoleOrder = nil
+		logAction("console_order_reset")
+	} else {
+		settings.ConsoleOrder = make([]string, 0, len(result.Items))
+		for _, item := range result.Items {
+			if name, ok := item.Metadata.(string); ok {
+				settings.ConsoleOrder = append(settings.ConsoleOrder, name)
+			}
+		}
+		logAction("console_order_saved", "order", strings.Join(settings.ConsoleOrder, "|"))
+	}
+	showOperationError("saving settings", "save settings", saveSettings(settings))
+}
+
+// ── Sort prefixes ────────────────────────────────────────────
+
+// sortPrefixStartupFlow checks the sort prefixes in settings.json at startup. Prefixes
+// rejected by checkSortPrefixes are cleared and reported. When the prefixes changed since
+// the last run, it asks before renaming existing shortcuts to them, since NextUI's recents
+// and saves follow the old folder names; declining puts the previous prefixes back.
+func sortPrefixStartupFlow() {
+	settings, problems := checkSortPrefixes(loadSettings())
+	if len(problems) > 0 {
+		log.Printf("sortPrefixStartupFlow: %s", strings.Join(problems, "; "))
+		logError("saving settings", saveSettings(settings))
+		showError("Sort prefix ignored, using the default:\n\n" + strings.Join(problems, "\n"))
+	}
+	if !sortPrefixesChanged(settings) {
+		return
+	}
+
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		logError("scanning shortcuts", err)
+		return
+	}
+	renames := pendingPrefixRenames(shortcuts, settings.sortPrefixes())
+	if len(renames) > 0 {
+		result, err := confirmationMessage(
+			fmt.Sprintf("The sort prefixes changed.\n\nRename %d shortcut(s) to use them?\n\nNextUI recents and saves made through\na shortcut keep its old name.", len(renames)),
+			[]gaba.FooterHelpItem{
+				{HelpText: "Keep old"},
+				{HelpText: "Rename", IsConfirmButton: true},
+			},
+		)
+		if isErrCancelled(err) || result == nil || !result.Confirmed {
+			log.Printf("sortPrefixStartupFlow: kept %d shortcut(s), restoring the previous prefixes", len(renames))
+			settings.BottomPrefix, settings.TopPrefix = settings.AppliedBottomPrefix, settings.AppliedTopPrefix
+			logError("saving settings", saveSettings(settings))
+			return
+		}
+	}
+	if _, err := migrateShortcutPrefixes(settings, renames); err != nil {
+		logError("migrating shortcut prefixes", err)
+	}
+}
+
+// ── Media management flow ────────────────────────────────────
+
+func manageMediaFlow() {
+	items := []gaba.MenuItem{
+		{Text: "Regenerate artwork"},
+		{Text: "Remove artwork"},
+		{Text: "Export artwork to ZIP"},
+		{Text: "Import artwork from ZIP"},
+		{Text: "Artwork report"},
+		{Text: "Artwork statistics"},
+	}
+
+	opts := gaba.DefaultListOptions("Manage Artwork", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+
+	result, err := gaba.List(opts)
+	if isErrCancelled(err) || err != nil || len(result.Selected) == 0 {
+		return
+	}
+
+	switch result.Selected[0] {
+	case 0:
+		regenerateAllMediaFlow()
+	case 1:
+		removeAllMediaFlow()
+	case 2:
+		exportArtworkFlow()
+	case 3:
+		importArtworkFlow()
+	case 4:
+		artworkReportFlow()
+	case 5:
+		artworkStatisticsScreen()
+	}
+}
+
+// artworkStatisticsScreen shows the totals of every artwork generation so far, kept in
+// settings.json by flushArtTotals.
+func artworkStatisticsScreen() {
+	logError("saving artwork totals", flushArtTotals())
+	t := loadSettings().ArtTotals
+
+	average := "-"
+	if runs := t.Generated + t.Skipped + t.Failed; runs > 0 {
+		average = (t.Duration / time.Duration(runs)).Round(time.Millisecond).String()
+	}
+	metadata := []gaba.MetadataItem{
+		{Label: "Generated", Value: fmt.Sprintf("%d", t.Generated)},
+		{Label: "Skipped", Value: fmt.Sprintf("%d", t.Skipped)},
+		{Label: "Failed", Value: fmt.Sprintf("%d", t.Failed)},
+		{Label: "Total time", Value: t.Duration.Round(100 * time.Millisecond).String()},
+		{Label: "Average time", Value: average},
+		{Label: "Source read", Value: fmt.Sprintf("%.1f MB", float64(t.SourceBytes)/(1<<20))},
+		{Label: "Written", Value: fmt.Sprintf("%.1f MB", float64(t.OutputBytes)/(1<<20))},
+	}
+
+	detailOpts := gaba.DefaultInfoScreenOptions()
+	detailOpts.Sections = []gaba.Section{
+		gaba.NewInfoSection("All artwork generated so far", metadata),
+	}
+	detailOpts.ShowThemeBackground = true
+	detailOpts.ShowScrollbar = false
+	gaba.DetailScreen("Artwork Statistics", detailOpts, []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+	})
+}
+
+// ── Health check ─────────────────────────────────────────────
+
+// healthScan holds the results of the health check scans. scanHealth runs them once, and
+// the menu labels and the check screens share the results.
+type healthScan struct {
+	stale    []Shortcut
+	staleErr error
+
+	mismatched    []Shortcut
+	mismatchedErr error
+
+	missing    []MissingConsoleDir
+	missingErr error
+}
+
+// scanHealth runs the health check scans in the background behind a progress message.
+func scanHealth(settings AppSettings) healthScan {
+	var h healthScan
+	gaba.ProcessMessage("Checking shortcuts...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			h.stale, h.staleErr = findStaleShortcuts(staleShortcutDays, settings)
+			h.mismatched, h.mismatchedErr = findMismatchedArtwork(settings)
+			h.missing, h.missingErr = findMissingConsoleDirs(settings)
+			return nil, nil
+		},
+	)
+	return h
+}
+
+// healthCheckFlow shows the available shortcut checks.
+func healthCheckFlow() {
+	settings := loadSettings()
+	scan := scanHealth(settings)
+	staleLabel := "Stale shortcuts"
+	if scan.staleErr != nil {
+		logError("finding stale shortcuts", scan.staleErr)
+	} else {
+		staleLabel += fmt.Sprintf("  (%d)", len(scan.stale))
+	}
+	sizeLabel := "Artwork size"
+	if scan.mismatchedErr != nil {
+		logError("checking artwork size", scan.mismatchedErr)
+	} else {
+		sizeLabel += fmt.Sprintf("  (%d)", len(scan.mismatched))
+	}
+	renameLabel := "Fix console rename"
+	if scan.missingErr != nil {
+		logError("finding missing console folders", scan.missingErr)
+	} else {
+		renameLabel += fmt.Sprintf("  (%d)", len(scan.missing))
+	}
+	items := []gaba.MenuItem{
+		{Text: "Duplicate targets"},
+		{Text: staleLabel},
+		{Text: sizeLabel},
+		{Text: renameLabel},
+	}
+
+	opts := gaba.DefaultListOptions("Health Check", items)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 {
+		return
+	}
+
+	switch result.Selected[0] {
+	case 0:
+		duplicateTargetsFlow()
+	case 1:
+		staleShortcutsFlow(scan, settings)
+	case 2:
+		mismatchedArtworkFlow(scan, settings)
+	case 3:
+		consoleRenameFixFlow(scan, settings)
+	}
+}
+
+// consoleRenameFixFlow lists the console folders scan found that ROM shortcuts point into
+// but that are gone from Roms/, asks which folder each was renamed to and repoints the
+// shortcuts.
+func consoleRenameFixFlow(scan healthScan, settings AppSettings) {
+	missing := scan.missing
+	if scan.missingErr != nil {
+		showError("Could not read shortcuts.")
+		return
+	}
+
+	items := make([]gaba.MenuItem, len(missing))
+	for i, m := range missing {
+		items[i] = gaba.MenuItem{Text: fmt.Sprintf("%s  (%d)", m.Name, m.Shortcuts)}
+	}
+	opts := gaba.DefaultListOptions("Missing Console Folders", items)
+	opts.EmptyMessage = "All ROM shortcuts point at\nexisting console folders."
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Pick new folder"},
+	}
+
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 || len(missing) == 0 {
+		return
+	}
+	old := missing[result.Selected[0]]
+
+	consoles, err := scanConsoleDirs(true, settings.sortPrefixes())
+	if err != nil {
+		logError("scanning consoles", err)
+		showError("Could not read ROM folders.")
+		return
+	}
+	// Folders with the old tag are the likeliest new names, so list them first.
+	oldTag := extractTag(old.Name)
+	sort.SliceStable(consoles, func(i, j int) bool {
+		return consoles[i].Tag == oldTag && consoles[j].Tag != oldTag
+	})
+	consoleItems := make([]gaba.MenuItem, len(consoles))
+	for i, c := range consoles {
+		consoleItems[i] = gaba.MenuItem{Text: c.Name}
+	}
+	opts = gaba.DefaultListOptions("Renamed To?", consoleItems)
+	opts.EmptyMessage = "No ROM folders found."
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Select"},
+	}
+	result, err = gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 || len(consoles) == 0 {
+		return
 	}
+	newName := consoles[result.Selected[0]].Name
 
-	metadata := []gaba.MetadataItem{
-		{Label: "Name", Value: sc.Display},
-		{Label: "Type", Value: kind},
-		{Label: "Tag", Value: sc.Tag},
+	confirmed, err := confirmationMessage(
+		fmt.Sprintf("Point %d shortcut(s) from\n\n%s\n\nto\n\n%s?", old.Shortcuts, old.Name, newName),
+		[]gaba.FooterHelpItem{
+			{HelpText: "Cancel"},
+			{HelpText: "Update", IsConfirmButton: true},
+		},
+	)
+	if err != nil || confirmed == nil || !confirmed.Confirmed {
+		return
 	}
 
-	if sc.TargetPath != "" {
-		metadata = append(metadata, gaba.MetadataItem{
-			Label: "Target", Value: sc.TargetPath,
-		})
+	var updated int
+	_, err = gaba.ProcessMessage("Updating shortcuts...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			var err error
+			updated, err = updateShortcutsForConsoleDirChange(old.Name, newName, settings)
+			return nil, err
+		},
+	)
+	if err != nil {
+		showOperationError("updating shortcuts for console rename", "update the shortcuts", err)
+		return
 	}
+	logAction("console_rename_fixed", "from", old.Name, "to", newName, "updated", updated)
 
-	sections := []gaba.Section{
-		gaba.NewInfoSection("Shortcut Info", metadata),
+	msg := fmt.Sprintf("Updated %d of %d shortcut(s).", updated, old.Shortcuts)
+	if updated < old.Shortcuts {
+		msg += "\n\nThe rest have no matching ROM\nin the new folder."
 	}
+	confirmationMessage(msg,
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
+}
 
-	detailOpts := gaba.DefaultInfoScreenOptions()
-	detailOpts.Sections = sections
-	detailOpts.ShowThemeBackground = true
-	detailOpts.ShowScrollbar = false
-	detailOpts.ConfirmButton = constants.VirtualButtonA
+// mismatchedArtworkFlow lists the shortcuts scan found with a bg.png made for another
+// screen size and offers to regenerate just those.
+func mismatchedArtworkFlow(scan healthScan, settings AppSettings) {
+	mismatched := scan.mismatched
+	if scan.mismatchedErr != nil {
+		showError("Could not read shortcuts.")
+		return
+	}
 
-	footer := []gaba.FooterHelpItem{
+	items := make([]gaba.MenuItem, len(mismatched))
+	for i, sc := range mismatched {
+		_, w, h := validateArtworkDimensions(sc)
+		items[i] = gaba.MenuItem{Text: fmt.Sprintf("%s  (%d×%d)", sc.Display, w, h)}
+	}
+	screenW, screenH := screenDimensions()
+	opts := gaba.DefaultListOptions(fmt.Sprintf("Artwork Not %d×%d", screenW, screenH), items)
+	opts.EmptyMessage = "All artwork matches this screen."
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Back"},
-		{ButtonName: "A", HelpText: "Delete", IsConfirmButton: true},
+		{ButtonName: "A", HelpText: "Regenerate these"},
 	}
 
-	_, err := gaba.DetailScreen(sc.Display, detailOpts, footer)
-	if isErrCancelled(err) {
-		return detailActionBack
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 || len(mismatched) == 0 {
+		return
 	}
 
-	// User pressed A — confirm deletion
-	return confirmDelete(sc)
+	var totals ArtTotals
+	_, err = gaba.ProcessMessage(fmt.Sprintf("Regenerating artwork for %d shortcut(s)...", len(mismatched)),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			var err error
+			totals, err = regenerateMedia(mismatched, settings, nil)
+			return nil, err
+		},
+	)
+	if err != nil {
+		showOperationError("regenerating artwork", "regenerate artwork", err)
+		return
+	}
+	logAction("artwork_regenerated", "reason", "size", "generated", totals.Generated,
+		"skipped", totals.Skipped, "failed", totals.Failed)
+
+	confirmationMessage(
+		fmt.Sprintf("Generated: %d   Skipped: %d   Failed: %d", totals.Generated, totals.Skipped, totals.Failed),
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
 }
 
-func confirmDelete(sc Shortcut) detailAction {
-	msg := fmt.Sprintf("Delete shortcut?\n\n%s\n\nThis will remove the shortcut\nfrom the main menu.", sc.Display)
+// staleShortcutsFlow lists the shortcuts scan found with a target missing for
+// staleShortcutDays and offers to remove them all.
+func staleShortcutsFlow(scan healthScan, settings AppSettings) {
+	stale := scan.stale
+	if scan.staleErr != nil {
+		showError("Could not read shortcuts.")
+		return
+	}
+
+	items := make([]gaba.MenuItem, len(stale))
+	for i, sc := range stale {
+		days := int(time.Since(sc.TargetMissingSince).Hours() / 24)
+		items[i] = gaba.MenuItem{Text: fmt.Sprintf("%s  (%s, missing %dd)", sc.Display, sc.Tag, days)}
+	}
+	opts := gaba.DefaultListOptions(fmt.Sprintf("Stale Shortcuts (%d)", len(stale)), items)
+	opts.EmptyMessage = fmt.Sprintf("No shortcut targets have been missing\nfor %d days or more.", staleShortcutDays)
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Remove all stale"},
+	}
+
+	result, err := gaba.List(opts)
+	if err != nil || len(result.Selected) == 0 || len(stale) == 0 {
+		return
+	}
 
-	result, err := gaba.ConfirmationMessage(msg,
+	confirmed, err := confirmationMessage(
+		fmt.Sprintf("Remove %d stale shortcut(s)?\n\nTheir ROMs or tools are no longer\non the SD card.", len(stale)),
 		[]gaba.FooterHelpItem{
-			{ButtonName: "B", HelpText: "Cancel"},
-			{ButtonName: "A", HelpText: "Delete", IsConfirmButton: true},
-		},
-		gaba.MessageOptions{
-			ConfirmButton: constants.VirtualButtonA,
+			{HelpText: "Cancel"},
+			{HelpText: "Remove", IsConfirmButton: true},
 		},
 	)
-	if isErrCancelled(err) || result == nil || !result.Confirmed {
-		return detailActionBack
+	if err != nil || confirmed == nil || !confirmed.Confirmed {
+		return
 	}
 
-	// Delete the shortcut
-	gaba.ProcessMessage("Removing shortcut...",
+	removed := 0
+	_, err = gaba.ProcessMessage("Removing stale shortcuts...",
 		gaba.ProcessMessageOptions{ShowThemeBackground: true},
 		func() (any, error) {
-			return nil, removeShortcut(sc.Path)
+			for _, sc := range stale {
+				if err := removeShortcut(sc.Path); err != nil {
+					logError("removing stale shortcut", err)
+					continue
+				}
+				removed++
+				logAction("shortcut_deleted", "folder", sc.Name, "target", sc.TargetPath, "reason", "stale")
+			}
+			if removed > 0 {
+				logError("updating shortcut registry", updateShortcutRegistry(settings))
+			}
+			return nil, nil
 		},
 	)
+	logError("removing stale shortcuts", err)
 
-	gaba.ConfirmationMessage(
-		"Shortcut removed.",
+	confirmationMessage(
+		fmt.Sprintf("Removed %d of %d stale shortcut(s).", removed, len(stale)),
 		[]gaba.FooterHelpItem{
-			{ButtonName: "A", HelpText: "OK", IsConfirmButton: true},
-		},
-		gaba.MessageOptions{
-			ConfirmButton: constants.VirtualButtonA,
+			{HelpText: "OK", IsConfirmButton: true},
 		},
 	)
-
-	return detailActionDeleted
 }
 
-// ── Settings screen ──────────────────────────────────────────
-
-// showSettingsScreen presents the global settings screen.
-// Users cycle Left/Right to change values and press A to save, or B to discard.
-func showSettingsScreen() {
+// duplicateTargetsFlow lists targets shared by several shortcuts. Selecting one keeps the
+// first shortcut (by display name) and offers to delete the others.
+func duplicateTargetsFlow() {
 	settings := loadSettings()
+	for {
+		groups, err := findDuplicateTargets(settings)
+		if err != nil {
+			logError("finding duplicate targets", err)
+			showError("Could not read shortcuts.")
+			return
+		}
+
+		targets := make([]string, 0, len(groups))
+		for target := range groups {
+			targets = append(targets, target)
+		}
+		sort.Slice(targets, func(i, j int) bool {
+			return strings.ToLower(filepath.Base(targets[i])) < strings.ToLower(filepath.Base(targets[j]))
+		})
+
+		items := make([]gaba.MenuItem, len(targets))
+		for i, target := range targets {
+			items[i] = gaba.MenuItem{Text: fmt.Sprintf("%s  (%d shortcuts)", filepath.Base(target), len(groups[target]))}
+		}
+		opts := gaba.DefaultListOptions("Duplicate Targets", items)
+		opts.EmptyMessage = "No two shortcuts share a target."
+		opts.FooterHelpItems = []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "Back"},
+			{ButtonName: "A", HelpText: "Fix"},
+		}
+
+		result, err := gaba.List(opts)
+		if err != nil || len(result.Selected) == 0 || len(targets) == 0 {
+			return
+		}
+		group := groups[targets[result.Selected[0]]]
+		keep, extras := group[0], group[1:]
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Keep \"%s\" and delete:\n", keep.Display)
+		for _, sc := range extras {
+			fmt.Fprintf(&sb, "\n%s", sc.Name)
+		}
+		confirmed, err := confirmationMessage(sb.String(),
+			[]gaba.FooterHelpItem{
+				{HelpText: "Cancel"},
+				{HelpText: "Delete", IsConfirmButton: true},
+			},
+		)
+		if err != nil || confirmed == nil || !confirmed.Confirmed {
+			continue
+		}
 
-	initialArtwork := 0
-	if settings.CopyArtwork {
-		initialArtwork = 1
+		for _, sc := range extras {
+			if err := removeShortcut(sc.Path); err != nil {
+				logError("removing duplicate shortcut", err)
+				continue
+			}
+			logAction("shortcut_deleted", "folder", sc.Name, "target", sc.TargetPath, "reason", "duplicate")
+		}
+		logError("updating shortcut registry", updateShortcutRegistry(settings))
 	}
+}
 
-	initialShowHidden := 0
-	if settings.ShowHidden {
-		initialShowHidden = 1
+// artworkReportFlow lists shortcuts missing bg.png while others of their type have one,
+// and explains for each where the source art is expected.
+func artworkReportFlow() {
+	settings := loadSettings()
+	missing, err := checkArtworkConsistency(settings)
+	if err != nil {
+		logError("checking artwork", err)
+		showError("Could not read shortcuts.")
+		return
 	}
 
-	items := []gaba.ItemWithOptions{
-		{
-			Item: gaba.MenuItem{Text: "Copy artwork when available"},
-			Options: []gaba.Option{
-				{DisplayName: "Off", Value: false},
-				{DisplayName: "On", Value: true},
-			},
-			SelectedOption: initialArtwork,
-		},
-		{
-			Item: gaba.MenuItem{Text: "Artwork mode"},
-			Options: []gaba.Option{
-				{DisplayName: "Art on Black background", Value: ArtworkModeBlack},
-				{DisplayName: "Art on Main menu Wallpaper", Value: ArtworkModeWallpaper},
-				{DisplayName: "Fallback to wallpaper", Value: ArtworkModeFallback},
-			},
-			SelectedOption: settings.ArtworkMode,
-		},
-		{
-			Item: gaba.MenuItem{Text: "Show hidden/disabled/empty ROMs"},
-			Options: []gaba.Option{
-				{DisplayName: "Off", Value: false},
-				{DisplayName: "On", Value: true},
+	items := make([]gaba.MenuItem, len(missing))
+	for i, sc := range missing {
+		kind := "ROM"
+		if sc.IsTool {
+			kind = "Tool"
+		}
+		items[i] = gaba.MenuItem{Text: fmt.Sprintf("%s  [%s]", sc.Display, kind)}
+	}
+	opts := gaba.DefaultListOptions("Artwork Report", items)
+	opts.EmptyMessage = "Every shortcut has artwork."
+	opts.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Why?"},
+	}
+
+	for {
+		result, err := gaba.List(opts)
+		if err != nil || len(result.Selected) == 0 {
+			return
+		}
+		idx := result.Selected[0]
+		opts.SelectedIndex = idx
+		sc := missing[idx]
+
+		var msg string
+		switch src := firstExistingPath(resolveArtSrcPaths(sc, settings)); {
+		case src == "":
+			msg = fmt.Sprintf("%s has no bg.png.\n\nIts console folder could not be determined.", sc.Display)
+		case fileExists(src):
+			msg = fmt.Sprintf("%s has no bg.png, but source art exists at\n\n%s\n\nRegenerate artwork to create it.", sc.Display, src)
+		default:
+			msg = fmt.Sprintf("%s has no bg.png.\n\nSource art not found at\n\n%s", sc.Display, src)
+		}
+		confirmationMessage(msg,
+			[]gaba.FooterHelpItem{
+				{HelpText: "OK", IsConfirmButton: true},
 			},
-			SelectedOption: initialShowHidden,
-		},
+		)
 	}
+}
 
-	listOpts := gaba.OptionListSettings{
-		ConfirmButton: constants.VirtualButtonA,
-		FooterHelpItems: []gaba.FooterHelpItem{
-			{ButtonName: "B", HelpText: "Back"},
-			{ButtonName: "←/→", HelpText: "Change"},
-			{ButtonName: "A", HelpText: "Save"},
+func exportArtworkFlow() {
+	settings := loadSettings()
+	zipPath := artworkZipPath()
+	var exported int
+	_, err := gaba.ProcessMessage("Exporting artwork...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			var err error
+			exported, err = exportAllArtwork(zipPath, settings)
+			return nil, err
 		},
+	)
+	if err != nil {
+		showOperationError("exporting artwork", "export artwork", err)
+		return
 	}
+	logAction("artwork_exported", "path", zipPath, "count", exported)
 
-	result, err := gaba.OptionsList("Settings", listOpts, items)
-	if isErrCancelled(err) {
-		return // B pressed — discard changes
+	confirmationMessage(
+		fmt.Sprintf("Exported artwork for %d shortcut(s) to\n\n%s", exported, zipPath),
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
+}
+
+func importArtworkFlow() {
+	settings := loadSettings()
+	zipPath := artworkZipPath()
+	msg := fmt.Sprintf("Import artwork from\n\n%s?\n\nExisting bg.png files of matching\nshortcuts will be replaced.", zipPath)
+	confirmed, err := confirmationMessage(msg,
+		[]gaba.FooterHelpItem{
+			{HelpText: "Cancel"},
+			{HelpText: "Import", IsConfirmButton: true},
+		},
+	)
+	if isErrCancelled(err) || confirmed == nil || !confirmed.Confirmed {
+		return
 	}
+
+	var applied, skipped int
+	_, err = gaba.ProcessMessage("Importing artwork...",
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (any, error) {
+			var err error
+			applied, skipped, err = importAllArtwork(zipPath, settings)
+			return nil, err
+		},
+	)
 	if err != nil {
-		logError("settings screen", err)
+		showOperationError("importing artwork", "import "+artworkZipName, err)
 		return
 	}
+	logAction("artwork_imported", "path", zipPath, "applied", applied, "skipped", skipped)
 
-	if result != nil {
-		settings.CopyArtwork, _ = result.Items[0].Options[result.Items[0].SelectedOption].Value.(bool)
-		settings.ArtworkMode, _ = result.Items[1].Options[result.Items[1].SelectedOption].Value.(int)
-		settings.ShowHidden, _ = result.Items[2].Options[result.Items[2].SelectedOption].Value.(bool)
-		log.Printf("ui: settings saving: copyArtwork=%v artworkMode=%d showHidden=%v",
-			settings.CopyArtwork, settings.ArtworkMode, settings.ShowHidden)
-		logError("saving settings", saveSettings(settings))
-	}
+	confirmationMessage(
+		fmt.Sprintf("Applied %d artwork file(s).\nSkipped %d (no matching shortcut).", applied, skipped),
+		[]gaba.FooterHelpItem{
+			{HelpText: "OK", IsConfirmButton: true},
+		},
+	)
 }
 
-// ── Media management flow ────────────────────────────────────
+// artworkPreview is one row of the previewArtwork list.
+type artworkPreview struct {
+	Name      string
+	ThumbPath string // "" when there is nothing to show
+	Note      string // dominant color as #RRGGBB, or why there is no thumbnail
+}
 
-func manageMediaFlow() {
-	items := []gaba.MenuItem{
-		{Text: "Regenerate artwork"},
-		{Text: "Remove artwork"},
-	}
+// artPreviewPageSize caps how many shortcuts previewArtwork renders at once, so memory,
+// time and temporary files stay bounded however many shortcuts there are.
+const artPreviewPageSize = 8
+
+// renderArtPreviews renders shortcuts' artwork in memory and writes each thumbnail to
+// tmpDir as <slot>.png, slot being the index within the page, overwriting the previous
+// page's files.
+func renderArtPreviews(shortcuts []Shortcut, settings AppSettings, tmpDir string) []artworkPreview {
+	screenW, screenH := screenDimensions()
+	fraction := atomic.NewFloat64(0)
+	var previews []artworkPreview
+	gaba.ProcessMessage(fmt.Sprintf("Rendering previews for %d shortcut(s)...", len(shortcuts)),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true, ShowProgressBar: true, Progress: fraction},
+		func() (any, error) {
+			for i, sc := range shortcuts {
+				p := artworkPreview{Name: sc.Display}
+				useGlobalBg, forceBlack := settings.forShortcut(sc.Path).artworkBgParams()
+				artSrc := firstExistingPath(resolveArtSrcPaths(sc, settings))
+				if settings.SkipUserCrafted && isUserCraftedArtwork(sc, artSrc) {
+					p.Note = "kept (user-crafted)"
+				} else if canvas, err := generateArtworkBgDryRun(artSrc, useGlobalBg, forceBlack, settings); err != nil {
+					log.Printf("renderArtPreviews: %s: %v", sc.Name, err)
+					p.Note = "error"
+				} else if canvas == nil {
+					p.Note = "no art"
+				} else {
+					c := dominantColor(canvas)
+					p.Note = fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+					thumb := filepath.Join(tmpDir, fmt.Sprintf("%d.png", i))
+					if err := encodePNGFile(thumb, scaleToFit(canvas, screenW/3, screenH/2)); err != nil {
+						log.Printf("renderArtPreviews: %s: %v", sc.Name, err)
+					} else {
+						p.ThumbPath = thumb
+					}
+				}
+				previews = append(previews, p)
+				fraction.Store(float64(i+1) / float64(len(shortcuts)))
+			}
+			return nil, nil
+		},
+	)
+	return previews
+}
 
-	opts := gaba.DefaultListOptions("Manage Artwork", items)
-	opts.FooterHelpItems = []gaba.FooterHelpItem{
-		{ButtonName: "B", HelpText: "Back"},
-		{ButtonName: "A", HelpText: "Select"},
+// previewArtwork lists each shortcut's artwork rendered with the current settings, the
+// focused one shown as a thumbnail next to its dominant color. Only artPreviewPageSize
+// shortcuts are rendered at a time; X renders the next page. gabagool only draws list
+// images from files, so the thumbnails go to a temporary folder that is removed on
+// return; no shortcut media is touched. Captions aren't drawn, as in
+// generateArtworkBgDryRun. Reports whether the user chose to apply.
+func previewArtwork(shortcuts []Shortcut, settings AppSettings) bool {
+	if len(shortcuts) == 0 {
+		return true
 	}
-
-	result, err := gaba.List(opts)
-	if isErrCancelled(err) || err != nil || len(result.Selected) == 0 {
-		return
+	tmpDir, err := os.MkdirTemp("", "shortcuts-preview-")
+	if err != nil {
+		logError("creating preview folder", err)
+		return true // no preview, but don't block regeneration
 	}
+	defer os.RemoveAll(tmpDir)
 
-	switch result.Selected[0] {
-	case 0:
-		regenerateAllMediaFlow()
-	case 1:
-		removeAllMediaFlow()
+	pages := (len(shortcuts) + artPreviewPageSize - 1) / artPreviewPageSize
+	for page := 0; ; page = (page + 1) % pages {
+		start := page * artPreviewPageSize
+		end := min(start+artPreviewPageSize, len(shortcuts))
+		previews := renderArtPreviews(shortcuts[start:end], settings, tmpDir)
+
+		items := make([]gaba.MenuItem, len(previews))
+		for i, p := range previews {
+			items[i] = gaba.MenuItem{Text: p.Name + "  " + p.Note, ImageFilename: p.ThumbPath}
+		}
+		title := "Artwork Preview"
+		if pages > 1 {
+			title = fmt.Sprintf("Artwork Preview (%d/%d)", page+1, pages)
+		}
+		opts := gaba.DefaultListOptions(title, items)
+		opts.ShowImages = true
+		opts.FooterHelpItems = []gaba.FooterHelpItem{{ButtonName: "B", HelpText: "Cancel"}}
+		if pages > 1 {
+			opts.ActionButton = constants.VirtualButtonX
+			opts.FooterHelpItems = append(opts.FooterHelpItems, gaba.FooterHelpItem{ButtonName: "X", HelpText: "Next Page"})
+		}
+		opts.FooterHelpItems = append(opts.FooterHelpItems, gaba.FooterHelpItem{ButtonName: "A", HelpText: "Apply"})
+		result, err := gaba.List(opts)
+		if isErrCancelled(err) || err != nil || result == nil {
+			return false
+		}
+		if result.Action != gaba.ListActionTriggered {
+			return result.Action == gaba.ListActionSelected
+		}
 	}
 }
 
 func regenerateAllMediaFlow() {
+	settings := loadSettings()
 	msg := "Regenerate artwork for all shortcuts?\n\nThis will (re)create bg.png for every\nshortcut using the current Artwork mode."
-	confirmed, err := gaba.ConfirmationMessage(msg,
+	if mismatched, err := findMismatchedArtwork(settings); err == nil && len(mismatched) > 0 {
+		screenW, screenH := screenDimensions()
+		msg += fmt.Sprintf("\n\n%d shortcut(s) have artwork made for\na screen other than %d×%d.", len(mismatched), screenW, screenH)
+	}
+	confirmed, err := confirmationMessage(msg,
 		[]gaba.FooterHelpItem{
-			{ButtonName: "B", HelpText: "Cancel"},
-			{ButtonName: "A", HelpText: "Regenerate", IsConfirmButton: true},
+			{HelpText: "Cancel"},
+			{HelpText: "Regenerate", IsConfirmButton: true},
 		},
-		gaba.MessageOptions{ConfirmButton: constants.VirtualButtonA},
 	)
 	if isErrCancelled(err) || confirmed == nil || !confirmed.Confirmed {
 		return
 	}
 
-	settings := loadSettings()
-	gaba.ProcessMessage("Regenerating artwork...",
-		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+	shortcuts, err := scanShortcuts(settings)
+	if err != nil {
+		showOperationError("scanning shortcuts", "read shortcuts", err)
+		return
+	}
+	if !previewArtwork(shortcuts, settings) {
+		return
+	}
+
+	// gabagool can't update the message text while it's shown, so the total goes in the
+	// message and per-shortcut progress drives the progress bar (and the log).
+	total := len(shortcuts)
+	fraction := atomic.NewFloat64(0)
+	var totals ArtTotals
+	_, err = gaba.ProcessMessage(fmt.Sprintf("Regenerating artwork for %d shortcut(s)...", total),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true, ShowProgressBar: true, Progress: fraction},
 		func() (any, error) {
-			return nil, regenerateAllMedia(settings)
+			progress := make(chan MediaProgress)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for p := range progress {
+					log.Printf("ui: regenerating artwork… (%d/%d)", p.Completed, p.Total)
+					fraction.Store(float64(p.Completed) / float64(p.Total))
+				}
+			}()
+			var err error
+			totals, err = regenerateMedia(shortcuts, settings, progress)
+			<-done
+			return nil, err
 		},
 	)
+	if err != nil {
+		showOperationError("regenerating artwork", "regenerate artwork", err)
+		return
+	}
+	logAction("artwork_regenerated", "artworkMode", settings.ArtworkMode,
+		"generated", totals.Generated, "skipped", totals.Skipped, "failed", totals.Failed)
 
-	gaba.ConfirmationMessage(
-		"Artwork regenerated for all shortcuts.",
+	confirmationMessage(
+		fmt.Sprintf("Artwork regenerated for all shortcuts.\n\nGenerated: %d   Skipped: %d   Failed: %d\nTime: %v   Written: %.1f MB",
+			totals.Generated, totals.Skipped, totals.Failed,
+			totals.Duration.Round(100*time.Millisecond), float64(totals.OutputBytes)/(1<<20)),
 		[]gaba.FooterHelpItem{
-			{ButtonName: "A", HelpText: "OK", IsConfirmButton: true},
+			{HelpText: "OK", IsConfirmButton: true},
 		},
-		gaba.MessageOptions{ConfirmButton: constants.VirtualButtonA},
 	)
 }
 
 func removeAllMediaFlow() {
-	msg := "Remove all artwork?\n\nThis will delete bg.png from every\nshortcut's .media folder."
-	confirmed, err := gaba.ConfirmationMessage(msg,
+	settings := loadSettings()
+	msg := "Remove all artwork?\n\nThis will delete bg.png from every\nshortcut's " + settings.mediaSubdir() + " folder."
+	confirmed, err := confirmationMessage(msg,
 		[]gaba.FooterHelpItem{
-			{ButtonName: "B", HelpText: "Cancel"},
-			{ButtonName: "A", HelpText: "Remove", IsConfirmButton: true},
+			{HelpText: "Cancel"},
+			{HelpText: "Remove", IsConfirmButton: true},
 		},
-		gaba.MessageOptions{ConfirmButton: constants.VirtualButtonA},
 	)
 	if isErrCancelled(err) || confirmed == nil || !confirmed.Confirmed {
 		return
 	}
 
-	gaba.ProcessMessage("Removing artwork...",
+	_, err = gaba.ProcessMessage("Removing artwork...",
 		gaba.ProcessMessageOptions{ShowThemeBackground: true},
 		func() (any, error) {
-			return nil, removeAllMedia()
+			return nil, removeAllMedia(settings)
 		},
 	)
+	if err != nil {
+		showOperationError("removing artwork", "remove artwork", err)
+		return
+	}
+	logAction("artwork_removed")
 
-	gaba.ConfirmationMessage(
+	confirmationMessage(
 		"Artwork removed from all shortcuts.",
 		[]gaba.FooterHelpItem{
-			{ButtonName: "A", HelpText: "OK", IsConfirmButton: true},
+			{HelpText: "OK", IsConfirmButton: true},
 		},
-		gaba.MessageOptions{ConfirmButton: constants.VirtualButtonA},
 	)
 }
 
 // ── Utility screens ──────────────────────────────────────────
 
+// dialogButtonNames maps the button names accepted by the confirm_button setting to
+// gabagool buttons.
+var dialogButtonNames = []struct {
+	name   string
+	button constants.VirtualButton
+}{
+	{"A", constants.VirtualButtonA},
+	{"B", constants.VirtualButtonB},
+	{"X", constants.VirtualButtonX},
+	{"Y", constants.VirtualButtonY},
+	{"Start", constants.VirtualButtonStart},
+	{"Select", constants.VirtualButtonSelect},
+}
+
+// virtualButtonFromString returns the button named s (case-insensitive, e.g. "b" or
+// "Start"), or A for an empty or unknown name.
+func virtualButtonFromString(s string) constants.VirtualButton {
+	for _, b := range dialogButtonNames {
+		if strings.EqualFold(strings.TrimSpace(s), b.name) {
+			return b.button
+		}
+	}
+	return constants.VirtualButtonA
+}
+
+// virtualButtonName returns the footer label for button b.
+func virtualButtonName(b constants.VirtualButton) string {
+	for _, n := range dialogButtonNames {
+		if n.button == b {
+			return n.name
+		}
+	}
+	return "A"
+}
+
+// dialogButtons returns the confirm and cancel buttons of confirmation dialogs, from the
+// ConfirmButton setting. Cancel stays on B unless B confirms, in which case A cancels.
+func dialogButtons(s AppSettings) (confirm, cancel constants.VirtualButton) {
+	confirm = virtualButtonFromString(s.ConfirmButton)
+	if confirm == constants.VirtualButtonB {
+		return confirm, constants.VirtualButtonA
+	}
+	return confirm, constants.VirtualButtonB
+}
+
+// confirmationMessage shows gaba.ConfirmationMessage with the configured buttons, read
+// once per dialog. Footer items are labelled with the confirm button when IsConfirmButton
+// is set and with the cancel button otherwise.
+func confirmationMessage(message string, footer []gaba.FooterHelpItem) (*gaba.ConfirmationResult, error) {
+	confirm, cancel := dialogButtons(loadSettings())
+	for i := range footer {
+		if footer[i].IsConfirmButton {
+			footer[i].ButtonName = virtualButtonName(confirm)
+		} else {
+			footer[i].ButtonName = virtualButtonName(cancel)
+		}
+	}
+	return gaba.ConfirmationMessage(message, footer, gaba.MessageOptions{ConfirmButton: confirm, CancelButton: cancel})
+}
+
 func showError(message string) {
-	gaba.ConfirmationMessage(message,
+	confirmationMessage(message,
 		[]gaba.FooterHelpItem{
-			{ButtonName: "B", HelpText: "Back"},
+			{HelpText: "Back"},
 		},
-		gaba.MessageOptions{},
 	)
 }
+
+// showOperationError logs err under context and, unless it is nil or a cancellation,
+// tells the user which action failed and what to check, e.g.
+// "Could not create the shortcut.\n\nCheck the SD card is writable."
+func showOperationError(context, action string, err error) {
+	if err == nil || isErrCancelled(err) {
+		return
+	}
+	logError(context, err)
+	showError(fmt.Sprintf("Could not %s.\n\n%s", action, errorHint(err)))
+}
+
+// artworkNote turns the artErr of a create call into a note for the success message: the
+// shortcut exists, only its bg.png is missing. Returns "" when artErr is nil.
+func artworkNote(artErr error) string {
+	if artErr == nil {
+		return ""
+	}
+	logError("generating artwork", artErr)
+	return "\n\nIts artwork could not be generated.\n" + errorHint(artErr)
+}
+
+// errorHint suggests a fix for common filesystem errors without showing raw paths.
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrPermission), errors.Is(err, syscall.EROFS):
+		return "Check the SD card is writable."
+	case errors.Is(err, syscall.ENOSPC):
+		return "The SD card may be full."
+	case errors.Is(err, errNoEmusDir):
+		return "No Emus folder was found on this card.\nCheck that NextUI is installed."
+	case errors.Is(err, fs.ErrNotExist):
+		return "A file or folder is missing.\nRescan or check the SD card."
+	default:
+		return "See shortcuts.log for details."
+	}
+}